@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+)
+
+func listBids(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("bids list", flag.ContinueOnError)
+	order := fs.String("sort", client.BidsByName, "order of bids: price or delivery, by name when empty")
+	page := pageFlags(fs)
+	ids, err := parseArgs(fs, args, "TENDER_ID")
+	if err != nil {
+		return err
+	}
+
+	bids, err := api.ListTenderBids(ctx, ids[0], *page, *order)
+	if err != nil {
+		return err
+	}
+	return out.print(bids, bidHeader, bidRows(bids...))
+}
+
+func rollbackBid(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("bids rollback", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "BID_ID", "VERSION")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersion("VERSION", positional[1])
+	if err != nil {
+		return err
+	}
+
+	bid, err := api.RollbackBid(ctx, positional[0], version)
+	if err != nil {
+		return err
+	}
+	return out.print(bid, bidHeader, bidRows(bid))
+}
+
+func bidHistory(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("bids history", flag.ContinueOnError)
+	ids, err := parseArgs(fs, args, "BID_ID")
+	if err != nil {
+		return err
+	}
+
+	versions, err := client.All(ctx, func(ctx context.Context, page client.Page) ([]*client.Bid, error) {
+		return api.ListBidVersions(ctx, ids[0], page)
+	})
+	if err != nil {
+		return err
+	}
+	return out.print(versions, bidHeader, bidRows(versions...))
+}
+
+func diffBid(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("bids diff", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "BID_ID", "FROM", "TO")
+	if err != nil {
+		return err
+	}
+	from, err := parseVersion("FROM", positional[1])
+	if err != nil {
+		return err
+	}
+	to, err := parseVersion("TO", positional[2])
+	if err != nil {
+		return err
+	}
+
+	diff, err := api.DiffBidVersions(ctx, positional[0], from, to)
+	if err != nil {
+		return err
+	}
+	return out.print(diff, diffHeader, diffRows(diff))
+}
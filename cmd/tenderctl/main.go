@@ -0,0 +1,154 @@
+// tenderctl operates the tender api from command line acting as employee given by -user,
+// the employee needs the same permissions as in the api
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/config"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+)
+
+const usage = `usage: tenderctl [flags] <command> [arguments]
+
+commands:
+  tenders list [-published] [-type TYPE] [-limit N] [-offset N]
+  tenders show TENDER_ID
+  tenders close TENDER_ID [-version N]
+  tenders rollback TENDER_ID VERSION
+  tenders history TENDER_ID
+  tenders diff TENDER_ID FROM TO
+  bids list TENDER_ID [-sort price|delivery] [-limit N] [-offset N]
+  bids rollback BID_ID VERSION
+  bids history BID_ID
+  bids diff BID_ID FROM TO
+  responsibles list ORGANIZATION_ID
+  responsibles grant ORGANIZATION_ID EMPLOYEE_ID [-role ROLE]
+
+flags:
+`
+
+// command runs subcommand with its arguments
+type command func(ctx context.Context, api *client.Client, out *printer, args []string) error
+
+var commands = map[string]command{
+	"tenders list":       listTenders,
+	"tenders show":       showTender,
+	"tenders close":      closeTender,
+	"tenders rollback":   rollbackTender,
+	"tenders history":    tenderHistory,
+	"tenders diff":       diffTender,
+	"bids list":          listBids,
+	"bids rollback":      rollbackBid,
+	"bids history":       bidHistory,
+	"bids diff":          diffBid,
+	"responsibles list":  listResponsibles,
+	"responsibles grant": grantResponsible,
+}
+
+func main() {
+	cfg := config.LoadClient()
+
+	var orgId, format string
+	flag.StringVar(&cfg.URL, "url", cfg.URL, "base url of the api, API_URL")
+	flag.StringVar(&cfg.Username, "user", cfg.Username, "username of acting employee, TENDERCTL_USER")
+	flag.StringVar(&orgId, "org", "", "organization to act on behalf of")
+	flag.StringVar(&format, "o", formatTable, "output format: table or json")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cmd, ok := commands[strings.Join(flag.Args()[:min(2, flag.NArg())], " ")]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if format != formatTable && format != formatJSON {
+		log.Fatal("incorrect output format")
+	}
+	if cfg.Username == "" {
+		log.Fatal("username is required, set -user or TENDERCTL_USER")
+	}
+	if cfg.IssuerKey == "" {
+		log.Fatal("issuer key is required, set AUTH_ISSUER_KEY")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	api := client.New(cfg.URL, client.Options{Retries: 3})
+	token, err := api.IssueToken(ctx, cfg.Username, cfg.IssuerKey)
+	if err != nil {
+		log.Fatalf("unable to issue token: %s", err)
+	}
+	api = api.WithToken(token.Token)
+	if orgId != "" {
+		api = api.OnBehalf(orgId)
+	}
+
+	err = cmd(ctx, api, &printer{format: format, w: os.Stdout}, flag.Args()[2:])
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseArgs parses flags of subcommand placed before or after its positional arguments,
+// which are checked to match names
+func parseArgs(fs *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	fs.SetOutput(os.Stderr)
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != len(names) {
+		return nil, fmt.Errorf("usage: tenderctl %s %s", fs.Name(), strings.Join(names, " "))
+	}
+	return positional, nil
+}
+
+// parseVersion reads version number of argument name
+func parseVersion(name, value string) (int64, error) {
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, errors.New("incorrect " + name)
+	}
+	return version, nil
+}
+
+// pageFlags adds -limit and -offset to subcommand
+func pageFlags(fs *flag.FlagSet) *client.Page {
+	page := &client.Page{}
+	fs.Int64Var(&page.Limit, "limit", 0, "number of items, up to 50, 5 when zero")
+	fs.Int64Var(&page.Offset, "offset", 0, "number of skipped items")
+	return page
+}
+
+// stringList is repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes results as aligned table or as json of api responses
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+// print writes v as json or its rows as table under header
+func (p *printer) print(v interface{}, header []string, rows [][]string) error {
+	if p.format == formatJSON {
+		return p.json(v)
+	}
+	return p.table(header, rows)
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+var tenderHeader = []string{"ID", "NAME", "STATUS", "SERVICE TYPE", "VERSION", "DEADLINE", "CREATED"}
+
+func tenderRows(tenders ...*client.Tender) [][]string {
+	rows := make([][]string, 0, len(tenders))
+	for _, t := range tenders {
+		deadline := "-"
+		if t.Deadline != nil {
+			deadline = formatTime(*t.Deadline)
+		}
+		rows = append(rows, []string{t.Id, t.Name, t.Status, t.ServType, strconv.FormatInt(t.Version, 10), deadline, formatTime(t.Created)})
+	}
+	return rows
+}
+
+var bidHeader = []string{"ID", "NAME", "STATUS", "AUTHOR", "PRICE", "DELIVERY DAYS", "VERSION", "CREATED"}
+
+func bidRows(bids ...*client.Bid) [][]string {
+	rows := make([][]string, 0, len(bids))
+	for _, b := range bids {
+		price, days := "-", "-"
+		if b.Amount != nil {
+			price = strconv.FormatFloat(*b.Amount, 'f', 2, 64) + " " + b.Currency
+		}
+		if b.DeliveryDays != nil {
+			days = strconv.FormatInt(*b.DeliveryDays, 10)
+		}
+		author := b.AuthorType + " " + b.AuthorId
+		rows = append(rows, []string{b.Id, b.Name, b.Status, author, price, days, strconv.FormatInt(b.Version, 10), formatTime(b.Created)})
+	}
+	return rows
+}
+
+var diffHeader = []string{"FIELD", "FROM", "TO"}
+
+func diffRows(diff *client.VersionsDiff) [][]string {
+	rows := make([][]string, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		rows = append(rows, []string{change.Field, change.From, change.To})
+	}
+	return rows
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.DateTime)
+}
+
+// transpose turns single row into field and value rows
+func transpose(header, row []string) [][]string {
+	rows := make([][]string, 0, len(header))
+	for i := range header {
+		rows = append(rows, []string{header[i], row[i]})
+	}
+	return rows
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+)
+
+var memberHeader = []string{"ID", "USERNAME", "FIRST NAME", "LAST NAME", "ROLE"}
+
+func memberRows(members []*client.Member) [][]string {
+	rows := make([][]string, 0, len(members))
+	for _, m := range members {
+		rows = append(rows, []string{m.Id, m.Username, m.FirstName, m.LastName, m.Role})
+	}
+	return rows
+}
+
+func listResponsibles(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("responsibles list", flag.ContinueOnError)
+	ids, err := parseArgs(fs, args, "ORGANIZATION_ID")
+	if err != nil {
+		return err
+	}
+
+	members, err := api.ListResponsibles(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return out.print(members, memberHeader, memberRows(members))
+}
+
+// grantResponsible makes employee responsible for organization and prints its responsibles
+func grantResponsible(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("responsibles grant", flag.ContinueOnError)
+	role := fs.String("role", "", "role of employee, default role of access policy when empty")
+	ids, err := parseArgs(fs, args, "ORGANIZATION_ID", "EMPLOYEE_ID")
+	if err != nil {
+		return err
+	}
+
+	err = api.GrantResponsible(ctx, ids[0], ids[1], *role)
+	if err != nil {
+		return err
+	}
+
+	members, err := api.ListResponsibles(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return out.print(members, memberHeader, memberRows(members))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+)
+
+// listTenders lists tenders of organizations of the employee or published ones of all organizations
+func listTenders(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders list", flag.ContinueOnError)
+	published := fs.Bool("published", false, "list published tenders of all organizations")
+	var serviceTypes stringList
+	fs.Var(&serviceTypes, "type", "service type of published tenders, repeatable")
+	page := pageFlags(fs)
+	_, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var tenders []*client.Tender
+	if *published {
+		tenders, err = api.ListTenders(ctx, *page, serviceTypes...)
+	} else {
+		tenders, err = api.ListMyTenders(ctx, *page)
+	}
+	if err != nil {
+		return err
+	}
+	return out.print(tenders, tenderHeader, tenderRows(tenders...))
+}
+
+// showTender prints the latest version of tender with its criteria and attachments
+func showTender(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders show", flag.ContinueOnError)
+	ids, err := parseArgs(fs, args, "TENDER_ID")
+	if err != nil {
+		return err
+	}
+	tenderId := ids[0]
+
+	versions, err := client.All(ctx, func(ctx context.Context, page client.Page) ([]*client.Tender, error) {
+		return api.ListTenderVersions(ctx, tenderId, page)
+	})
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return client.ErrNotFound
+	}
+	tender := versions[len(versions)-1]
+
+	criteria, err := api.GetTenderCriteria(ctx, tenderId)
+	if err != nil {
+		return err
+	}
+	attachments, err := api.ListTenderAttachments(ctx, tenderId)
+	if err != nil {
+		return err
+	}
+
+	if out.format == formatJSON {
+		return out.json(struct {
+			Tender      *client.Tender       `json:"tender"`
+			Criteria    []*client.Criterion  `json:"criteria"`
+			Attachments []*client.Attachment `json:"attachments"`
+		}{tender, criteria, attachments})
+	}
+
+	err = out.table([]string{"FIELD", "VALUE"}, transpose(tenderHeader, tenderRows(tender)[0]))
+	if err != nil {
+		return err
+	}
+	out.w.Write([]byte("\n"))
+	rows := make([][]string, 0, len(criteria))
+	for _, c := range criteria {
+		rows = append(rows, []string{c.Id, c.Name, strconv.FormatInt(c.Weight, 10)})
+	}
+	err = out.table([]string{"CRITERION", "NAME", "WEIGHT"}, rows)
+	if err != nil {
+		return err
+	}
+	out.w.Write([]byte("\n"))
+	rows = make([][]string, 0, len(attachments))
+	for _, a := range attachments {
+		rows = append(rows, []string{a.Id, a.Name, a.ContentType, strconv.FormatInt(a.Size, 10)})
+	}
+	return out.table([]string{"ATTACHMENT", "NAME", "CONTENT TYPE", "SIZE"}, rows)
+}
+
+// closeTender closes tender, with -version only when it wasn't changed since
+func closeTender(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders close", flag.ContinueOnError)
+	version := fs.Int64("version", client.AnyVersion, "expected version of tender")
+	ids, err := parseArgs(fs, args, "TENDER_ID")
+	if err != nil {
+		return err
+	}
+
+	tender, err := api.UpdateTenderStatus(ctx, ids[0], client.TenderClosed, *version)
+	if err != nil {
+		return err
+	}
+	return out.print(tender, tenderHeader, tenderRows(tender))
+}
+
+// rollbackTender forces tender back to VERSION: it's restored as new latest version whatever
+// the current version is and whoever created the tender, -user needs tender.edit in its organization
+func rollbackTender(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders rollback", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "TENDER_ID", "VERSION")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersion("VERSION", positional[1])
+	if err != nil {
+		return err
+	}
+
+	tender, err := api.RollbackTender(ctx, positional[0], version)
+	if err != nil {
+		return err
+	}
+	return out.print(tender, tenderHeader, tenderRows(tender))
+}
+
+func tenderHistory(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders history", flag.ContinueOnError)
+	ids, err := parseArgs(fs, args, "TENDER_ID")
+	if err != nil {
+		return err
+	}
+
+	versions, err := client.All(ctx, func(ctx context.Context, page client.Page) ([]*client.Tender, error) {
+		return api.ListTenderVersions(ctx, ids[0], page)
+	})
+	if err != nil {
+		return err
+	}
+	return out.print(versions, tenderHeader, tenderRows(versions...))
+}
+
+func diffTender(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := flag.NewFlagSet("tenders diff", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, "TENDER_ID", "FROM", "TO")
+	if err != nil {
+		return err
+	}
+	from, err := parseVersion("FROM", positional[1])
+	if err != nil {
+		return err
+	}
+	to, err := parseVersion("TO", positional[2])
+	if err != nil {
+		return err
+	}
+
+	diff, err := api.DiffTenderVersions(ctx, positional[0], from, to)
+	if err != nil {
+		return err
+	}
+	return out.print(diff, diffHeader, diffRows(diff))
+}
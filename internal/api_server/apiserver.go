@@ -1,65 +1,306 @@
 package apiserver
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/config"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/health"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/metrics"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/openapi"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/ratelimit"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/scheduler"
+	attachmentservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/attachment"
+	auditservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/audit"
+	authservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/auth"
 	bidservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/bider"
+	organizationservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/organization"
 	tenderservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/tender"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/attachmentstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/auditstore"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/bidstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/blobstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/employeestore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/evaluationstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/memstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/organizationstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/querylog"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/responsiblestore"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/tenderstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/txstore"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
-// Start init's all connections and starts api's work
+// Start init's all connections and starts api's work, it returns after SIGINT or SIGTERM
+// when in-flight requests are drained and background workers are stopped
 func Start(cfg *config.Config) error {
 	// Get logger
 	log := setLog("debug")
 
-	// Get db connection
-	tenderSt, err := loadTenderStore(cfg.Db)
-	if err != nil {
-		return fmt.Errorf("unable to load tenders store error: %s", err)
+	// ctx is cancelled on shutdown signal
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+
+	// Get metrics registry
+	mtr := metrics.New(log)
+
+	var (
+		tenderSt      store.Tenders
+		responsibleSt store.Responsibles
+		orgSt         store.Organizations
+		employeeSt    store.Employees
+		bidSt         store.Bids
+		auditSt       store.Audit
+		evaluationSt  store.Evaluations
+		attachmentSt  store.Attachments
+		blobSt        store.BlobStore
+		unitOfWork    store.UnitOfWork
+		// readyCheck pings databases, memory backend is always ready
+		readyCheck health.Check
+		err        error
+	)
+	switch cfg.Db.Backend {
+	case config.BackendMemory:
+		db := memstore.NewDB()
+		if cfg.Db.Seed != "" {
+			err = db.LoadSeed(cfg.Db.Seed)
+			if err != nil {
+				return fmt.Errorf("unable to load memory store seed error: %s", err)
+			}
+		}
+		log.Warn("api uses in-memory storage, all data will be lost on shutdown")
+
+		tenderSt = memstore.NewTenderStore(db)
+		responsibleSt = memstore.NewResponsibleStore(db)
+		orgSt = memstore.NewOrganizationStore(db)
+		employeeSt = memstore.NewEmployeeStore(db)
+		bidSt = memstore.NewBidStore(db)
+		auditSt = memstore.NewAuditStore(db)
+		evaluationSt = memstore.NewEvaluationStore(db)
+		attachmentSt = memstore.NewAttachmentStore(db)
+		blobSt = memstore.NewBlobStore()
+		unitOfWork = memstore.NewUnitOfWork(db)
+	default:
+		// Get db connection pool shared by all stores
+		db, err := openDB(cfg.Db.Conn, cfg.Db.Pool)
+		if err != nil {
+			return fmt.Errorf("unable to connect to database error: %s", err)
+		}
+		defer db.Close()
+
+		err = mtr.RegisterDB(db, "primary")
+		if err != nil {
+			return fmt.Errorf("unable to register database metrics error: %s", err)
+		}
+
+		// Reading operations outside of transactions go to replica when it's set
+		var reader store.Querier = db
+		readyCheck = db.PingContext
+		if cfg.Db.ReplicaConn != "" {
+			replica, err := openDB(cfg.Db.ReplicaConn, cfg.Db.Pool)
+			if err != nil {
+				return fmt.Errorf("unable to connect to database replica error: %s", err)
+			}
+			defer replica.Close()
+
+			err = mtr.RegisterDB(replica, "replica")
+			if err != nil {
+				return fmt.Errorf("unable to register database replica metrics error: %s", err)
+			}
+
+			reader = store.NewReplicated(db, replica)
+			readyCheck = func(ctx context.Context) error {
+				err := db.PingContext(ctx)
+				if err != nil {
+					return err
+				}
+				err = replica.PingContext(ctx)
+				if err != nil {
+					return fmt.Errorf("replica: %w", err)
+				}
+				return nil
+			}
+			log.Info("reading operations are served by database replica")
+		}
+
+		// Queries of all stores are timed and logged with request id
+		timeouts := store.Timeouts{Read: cfg.Db.ReadTimeout, Write: cfg.Db.WriteTimeout}
+		wrap := func(q store.Querier) store.Querier {
+			return mtr.Querier(querylog.New(q, cfg.Db.SlowQuery, log))
+		}
+		q := wrap(reader)
+		tenderSt = tenderstore.New(q, timeouts)
+		responsibleSt = responsiblestore.New(q, timeouts)
+		orgSt = organizationstore.New(q, timeouts)
+		employeeSt = employeestore.New(q, timeouts)
+		bidSt = bidstore.New(q, timeouts)
+		auditSt = auditstore.New(q, timeouts)
+		evaluationSt = evaluationstore.New(q, timeouts)
+		attachmentSt = attachmentstore.New(q, timeouts)
+		unitOfWork = txstore.New(db, timeouts, wrap)
+
+		// Get file contents storage
+		blobSt, err = blobstore.NewLocal(cfg.Attachments.Dir)
+		if err != nil {
+			return fmt.Errorf("unable to open attachments storage error: %s", err)
+		}
 	}
 
-	responsibleSt, err := loadResponsibleStore(cfg.Db)
-	if err != nil {
-		return fmt.Errorf("unable to load responsibles store error: %s", err)
+	// Get access policy
+	pl := policy.Default()
+	if cfg.Policy.File != "" {
+		pl, err = policy.Load(cfg.Policy.File)
+		if err != nil {
+			return fmt.Errorf("unable to load access policy error: %s", err)
+		}
 	}
 
-	bidSt, err := loadBidStore(cfg.Db)
+	err = mtr.RegisterStores(tenderSt, bidSt)
 	if err != nil {
-		return fmt.Errorf("unable to load responsibles store error: %s", err)
+		return fmt.Errorf("unable to register store metrics error: %s", err)
 	}
 
+	// Check readiness in background so the api comes back once database recovers
+	monitor := health.New(readyCheck, health.Options{
+		Interval:   cfg.Health.Interval,
+		Timeout:    cfg.Health.Timeout,
+		MinBackoff: cfg.Health.MinBackoff,
+		MaxBackoff: cfg.Health.MaxBackoff,
+	}, log)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		monitor.Run(ctx)
+	}()
+
 	// Get Tender Service
-	TenderServ := tenderservice.New(tenderSt, responsibleSt, log)
-	BidsServ := bidservice.New(tenderSt, bidSt, responsibleSt, log)
+	TenderServ := tenderservice.New(tenderSt, responsibleSt, evaluationSt, unitOfWork, pl, log)
+
+	// Close tenders with passed deadline in background
+	if cfg.Scheduler.DeadlineInterval > 0 {
+		sched := scheduler.New(cfg.Scheduler.DeadlineInterval, log)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			sched.Run(ctx, "close expired tenders", func(ctx context.Context) error {
+				closed, err := TenderServ.CloseExpired(ctx, time.Now())
+				if closed != 0 {
+					log.Infof("closed %d tenders with passed deadline", closed)
+				}
+				return err
+			})
+		}()
+	}
 
 	// Get Bid Service
+	BidsServ := bidservice.New(tenderSt, bidSt, responsibleSt, evaluationSt, unitOfWork, pl, log)
+
+	// Get Auth Service
+	AuthServ := authservice.New(responsibleSt, cfg.Auth, log)
+
+	// Get Audit Service
+	AuditServ := auditservice.New(auditSt, responsibleSt, pl, log)
+
+	// Get Attachments Service
+	AttachmentsServ := attachmentservice.New(tenderSt, bidSt, responsibleSt, attachmentSt, blobSt, unitOfWork, cfg.Attachments.MaxSize, pl, log)
+
+	// Get Organizations Service
+	OrganizationsServ := organizationservice.New(orgSt, employeeSt, cfg.Auth.Admins, pl, log)
+
+	// Get rate limiters of reads and mutations
+	var readLimiter, mutationLimiter ratelimit.Limiter
+	if cfg.RateLimit.ReadRate > 0 {
+		readLimiter = ratelimit.NewMemory(ratelimit.Limit{Rate: cfg.RateLimit.ReadRate, Burst: cfg.RateLimit.ReadBurst})
+	}
+	if cfg.RateLimit.MutationRate > 0 {
+		mutationLimiter = ratelimit.NewMemory(ratelimit.Limit{Rate: cfg.RateLimit.MutationRate, Burst: cfg.RateLimit.MutationBurst})
+	}
+
+	// Get validator of requests against openapi specification
+	var validator *openapi.Validator
+	if cfg.OpenAPI.Validation != config.ValidationOff {
+		validator, err = openapi.Load(cfg.OpenAPI.Spec, "/api", cfg.OpenAPI.Validation == config.ValidationStrict)
+		if err != nil {
+			return fmt.Errorf("unable to load openapi specification error: %s", err)
+		}
+		log.Infof("requests are validated against %s in %s mode", cfg.OpenAPI.Spec, cfg.OpenAPI.Validation)
+	}
 
 	// Get server
-	srv := newServer(log, TenderServ, BidsServ)
+	srv := newServer(log, TenderServ, BidsServ, AuthServ, AuditServ, AttachmentsServ, OrganizationsServ, cfg.Auth.IssuerKey, readLimiter, mutationLimiter, mtr, monitor, validator)
+
+	httpSrv := &http.Server{
+		Addr:         ":" + cfg.Srv.Port,
+		Handler:      srv,
+		ReadTimeout:  cfg.Srv.ReadTimeout,
+		WriteTimeout: cfg.Srv.WriteTimeout,
+		IdleTimeout:  cfg.Srv.IdleTimeout,
+	}
 
 	log.Infof("api strted work on port: %s", cfg.Srv.Port)
 
 	// Start listner
-	err = http.ListenAndServe(":"+cfg.Srv.Port, srv)
+	served := make(chan error, 1)
+	go func() {
+		served <- httpSrv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-served:
+		stop()
+		workers.Wait()
+		return fmt.Errorf("api ended work with error: %s", err)
+	case <-ctx.Done():
+	}
+
+	log.Infof("api is shutting down, grace period %s", cfg.Srv.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Srv.ShutdownTimeout)
+	defer cancel()
+
+	// Drain connections, workers were stopped by ctx
+	err = httpSrv.Shutdown(shutdownCtx)
+	if err != nil {
+		return fmt.Errorf("unable to drain connections error: %s", err)
+	}
+	err = waitWorkers(shutdownCtx, &workers)
 	if err != nil {
-		log.Infof("api ended work with error: %s", err)
-	} else {
-		log.Info("api ended work")
+		return fmt.Errorf("unable to stop background workers error: %s", err)
 	}
 
+	// Database pool is closed by deferred Close
+	log.Info("api ended work")
 	return nil
 }
 
+// waitWorkers waits for workers until ctx is done
+func waitWorkers(ctx context.Context, workers *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func setLog(level string) *logrus.Logger {
 	log := logrus.New()
 	switch strings.ToLower(level) {
@@ -74,44 +315,44 @@ func setLog(level string) *logrus.Logger {
 	return log
 }
 
-func loadResponsibleStore(cfg config.Database) (store.Responsibles, error) {
-	db, err := sql.Open("postgres", cfg.Conn)
-	if err != nil {
-		return nil, fmt.Errorf("open: %v", err)
-	}
-
-	err = db.Ping()
+func openDB(conn string, pool config.Pool) (*sql.DB, error) {
+	conn, err := withStatementTimeout(conn, pool.StatementTimeout)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("parse connection string: %v", err)
 	}
 
-	return responsiblestore.New(db), nil
-}
-
-func loadTenderStore(cfg config.Database) (store.Tenders, error) {
-	db, err := sql.Open("postgres", cfg.Conn)
+	db, err := sql.Open("postgres", conn)
 	if err != nil {
 		return nil, fmt.Errorf("open: %v", err)
 	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
 	err = db.Ping()
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	return tenderstore.New(db), nil
+	return db, nil
 }
 
-func loadBidStore(cfg config.Database) (store.Bids, error) {
-	db, err := sql.Open("postgres", cfg.Conn)
-	if err != nil {
-		return nil, fmt.Errorf("open: %v", err)
+// withStatementTimeout adds statement_timeout run-time parameter to url or key=value
+// connection string, postgres cancels every statement running longer
+func withStatementTimeout(conn string, timeout time.Duration) (string, error) {
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if !strings.HasPrefix(conn, "postgres://") && !strings.HasPrefix(conn, "postgresql://") {
+		return conn + " statement_timeout=" + ms, nil
 	}
 
-	err = db.Ping()
+	u, err := url.Parse(conn)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-
-	return bidstore.New(db), nil
+	query := u.Query()
+	query.Set("statement_timeout", ms)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
 }
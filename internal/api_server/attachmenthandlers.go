@@ -0,0 +1,116 @@
+package apiserver
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/gorilla/mux"
+)
+
+// Attachment endpoints, the same handlers serve tenders and bids: entityType is one of
+// models.Attachment* entities and idVar is name of path variable holding its id
+
+func (s *server) handleAddAttachment(entityType, idVar string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: entity id
+		entityId := mux.Vars(r)[idVar]
+		if entityId == "" || len(entityId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// parse body: multipart form with "file" part, its content is streamed to the service
+		mr, err := r.MultipartReader()
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				s.error(w, r, ErrMissingFile)
+				return
+			}
+			if part.FormName() != "file" {
+				continue
+			}
+
+			att := &models.Attachment{
+				Name:        part.FileName(),
+				ContentType: part.Header.Get("Content-Type"),
+			}
+			if att.ContentType == "" {
+				att.ContentType = "application/octet-stream"
+			}
+			err = att.Validate()
+			if err != nil {
+				s.error(w, r, invalidBody(err))
+				return
+			}
+
+			// AttachmentsServ.Add()
+			data, err := s.AttachmentsServ.Add(r.Context(), entityType, entityId, att, part)
+			if err != nil {
+				s.error(w, r, err)
+				return
+			}
+			// responce data
+			s.respond(w, r, http.StatusOK, data)
+			return
+		}
+	})
+}
+
+func (s *server) handleGetAttachments(entityType, idVar string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: entity id
+		entityId := mux.Vars(r)[idVar]
+		if entityId == "" || len(entityId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// AttachmentsServ.List()
+		data, err := s.AttachmentsServ.List(r.Context(), entityType, entityId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleDownloadAttachment(entityType, idVar string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: entity id, attachmentId
+		entityId := mux.Vars(r)[idVar]
+		attachmentId := mux.Vars(r)["attachmentId"]
+		if entityId == "" || len(entityId) > 100 || attachmentId == "" || len(attachmentId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// AttachmentsServ.Open()
+		att, content, err := s.AttachmentsServ.Open(r.Context(), entityType, entityId, attachmentId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		defer content.Close()
+
+		// responce file content, digest identifies it so it serves as strong ETag
+		w.Header().Set("Content-Type", att.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(att.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.Name}))
+		w.Header().Set("ETag", `"`+att.Digest+`"`)
+		w.WriteHeader(http.StatusOK)
+		_, err = io.Copy(w, content)
+		if err != nil {
+			s.logger.Errorf("unable to send attachment %s error: %s", att.Id, err)
+		}
+	})
+}
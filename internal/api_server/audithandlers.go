@@ -0,0 +1,36 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Audit endpoints
+
+func (s *server) handleGetAuditLog() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+
+		// AuditServ.List()
+		data, err := s.AuditServ.List(r.Context(), orgId, limit, offset)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
@@ -0,0 +1,47 @@
+package apiserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Auth endpoints
+
+func (s *server) handleIssueToken() http.HandlerFunc {
+	type request struct {
+		Username string `json:"username"`
+	}
+	type response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// check issuer key, empty one never matches so misconfigured api issues nothing
+		key := r.Header.Get("X-Issuer-Key")
+		if s.issuerKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(s.issuerKey)) != 1 {
+			s.error(w, r, ErrInvalidCredentials)
+			return
+		}
+
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil || req.Username == "" || len(req.Username) > 50 {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// AuthServ.IssueToken()
+		token, expiresAt, err := s.AuthServ.IssueToken(r.Context(), req.Username)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce token
+		s.respond(w, r, http.StatusOK, &response{
+			Token:     token,
+			ExpiresAt: expiresAt,
+		})
+	})
+}
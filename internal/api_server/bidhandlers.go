@@ -2,12 +2,12 @@ package apiserver
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
 	"github.com/gorilla/mux"
 )
 
@@ -20,13 +20,17 @@ func (s *server) handleCreateBid() http.HandlerFunc {
 		TenderId   string `json:"tenderId"`
 		AuthorType string `json:"authorType"`
 		AuthorId   string `json:"authorId"`
+
+		Amount       *float64 `json:"amount"`
+		Currency     string   `json:"currency"`
+		DeliveryDays *int64   `json:"deliveryDays"`
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// get into model struct
 		req := &request{}
 		err := json.NewDecoder(r.Body).Decode(req)
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, ErrInvalidRequestBody)
 			return
 		}
 
@@ -36,89 +40,43 @@ func (s *server) handleCreateBid() http.HandlerFunc {
 			TenderId:    req.TenderId,
 			AuthorType:  req.AuthorType,
 			AuthorId:    req.AuthorId,
+
+			Amount:       req.Amount,
+			Currency:     req.Currency,
+			DeliveryDays: req.DeliveryDays,
 		}
 		// validate
 		err = b.Validate()
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, invalidBody(err))
 			return
 		}
 
 		// BidsServ.Create()
-		data, err := s.BidsServ.Create(b)
+		data, err := s.BidsServ.Create(r.Context(), b)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchTender) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
 
 func (s *server) handleGetUsersBids() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// parse querry: limit, offset, username
-		limitStr := r.URL.Query().Get("limit")
-		var limit int64 = 5
-		var err error
-		if len(limitStr) != 0 {
-			limit, err = strconv.ParseInt(limitStr, 10, 32)
-			if err != nil || limit < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		offsetStr := r.URL.Query().Get("offset")
-		var offset int64 = 5
-		if len(offsetStr) != 0 {
-			offset, err = strconv.ParseInt(offsetStr, 10, 32)
-			if err != nil || offset < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusUnauthorized, ErrInvalidQuerryParams)
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
 			return
 		}
 
 		// BidsServ.GetByName()
-		data, err := s.BidsServ.GetByName(limit, offset, username)
+		data, err := s.BidsServ.GetByName(r.Context(), limit, offset)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce [data, data, data]
@@ -131,57 +89,28 @@ func (s *server) handleGetTendersBids() http.HandlerFunc {
 		// parse path: tenderId
 		tenderId := mux.Vars(r)["tenderId"]
 		if tenderId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusUnauthorized, ErrInvalidQuerryParams)
-			return
-		}
 		// parse querry: limit, offset
-		limitStr := r.URL.Query().Get("limit")
-		var limit int64 = 5
-		var err error
-		if len(limitStr) != 0 {
-			limit, err = strconv.ParseInt(limitStr, 10, 32)
-			if err != nil || limit < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
 		}
 
-		offsetStr := r.URL.Query().Get("offset")
-		var offset int64 = 5
-		if len(offsetStr) != 0 {
-			offset, err = strconv.ParseInt(offsetStr, 10, 32)
-			if err != nil || offset < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
+		// parse querry: sort
+		order := r.URL.Query().Get("sort")
+		if order != store.BidsByName && order != store.BidsByPrice && order != store.BidsByDelivery {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
 		}
+
 		// BidsServ.GetTendersBids()
-		data, err := s.BidsServ.GetTenderBids(limit, offset, tenderId, username)
+		data, err := s.BidsServ.GetTenderBids(r.Context(), limit, offset, tenderId, order)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSucnResource) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// respoce [data, data, data]
@@ -189,43 +118,50 @@ func (s *server) handleGetTendersBids() http.HandlerFunc {
 	})
 }
 
+func (s *server) handleGetTenderRanking() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: tenderId
+		tenderId := mux.Vars(r)["tenderId"]
+		if tenderId == "" || len(tenderId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// parse querry: by
+		by := r.URL.Query().Get("by")
+		if by == "" {
+			by = services.RankByPrice
+		}
+		if by != services.RankByPrice && by != services.RankByScore {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// BidsServ.GetRanking()
+		data, err := s.BidsServ.GetRanking(r.Context(), tenderId, by)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
 func (s *server) handleInterractBidStatus() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			// parse path: bidId
 			bidId := mux.Vars(r)["bidId"]
 			if bidId == "" {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
 
-			// parse querry: username
-			username := r.URL.Query().Get("username")
-			if username == "" {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidQuerryParams)
-				return
-			}
 			// BidsServ.GetStat()
-			status, err := s.BidsServ.GetStat(bidId, username)
+			status, err := s.BidsServ.GetStat(r.Context(), bidId)
 			if err != nil {
-				if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-					s.DeadOnError(err)
-					s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchUser) {
-					s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-					return
-				}
-				if errors.Is(err, services.ErrNoPermitions) {
-					s.error(w, r, http.StatusForbidden, err)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchBid) {
-					s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-					return
-				}
-				s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+				s.error(w, r, err)
 				return
 			}
 			// response status
@@ -235,49 +171,35 @@ func (s *server) handleInterractBidStatus() http.HandlerFunc {
 			// parse path: bidId
 			bidId := mux.Vars(r)["bidId"]
 			if bidId == "" {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
-			// parse querry: status, username
+			// parse querry: status
 			status := r.URL.Query().Get("status")
 			if status == "" || (status != "Created" && status != "Published" && status != "Canceled") {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
 
-			username := r.URL.Query().Get("username")
-			if username == "" {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			// parse header: If-Match
+			ifMatch, err := parseIfMatch(r)
+			if err != nil {
+				s.error(w, r, ErrInvalidIfMatch)
 				return
 			}
+
 			// BidsServ.ChangeStat()
-			data, err := s.BidsServ.ChangeStat(bidId, status, username)
+			data, err := s.BidsServ.ChangeStat(r.Context(), bidId, status, ifMatch)
 			if err != nil {
-				if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-					s.DeadOnError(err)
-					s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchUser) {
-					s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-					return
-				}
-				if errors.Is(err, services.ErrNoPermitions) {
-					s.error(w, r, http.StatusForbidden, err)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchBid) {
-					s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-					return
-				}
-				s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+				s.error(w, r, err)
 				return
 			}
 			// response data
+			w.Header().Set("ETag", etag(data.Version))
 			s.respond(w, r, http.StatusOK, data)
 			return
 		}
-		s.error(w, r, http.StatusMethodNotAllowed, ErrUnsupportedMethod)
+		s.error(w, r, ErrUnsupportedMethod)
 	})
 }
 
@@ -285,105 +207,84 @@ func (s *server) handleEditBid() http.HandlerFunc {
 	type request struct {
 		Name  string `json:"name"`
 		Descr string `json:"description"`
+
+		Amount       *float64 `json:"amount"`
+		Currency     string   `json:"currency"`
+		DeliveryDays *int64   `json:"deliveryDays"`
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// parse body data
 		req := &request{}
 		err := json.NewDecoder(r.Body).Decode(req)
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, ErrInvalidRequestBody)
 			return
 		}
 
 		b := &models.Bid{
 			Name:        req.Name,
 			Description: req.Descr,
+
+			Amount:       req.Amount,
+			Currency:     req.Currency,
+			DeliveryDays: req.DeliveryDays,
+		}
+
+		// validate
+		err = b.ValidateEdition()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
 		}
 
 		// parse path: bidId
 		bidId := mux.Vars(r)["bidId"]
 		if bidId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
-		// parse querry: username
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+		// parse header: If-Match
+		ifMatch, err := parseIfMatch(r)
+		if err != nil {
+			s.error(w, r, ErrInvalidIfMatch)
 			return
 		}
+
 		// BidsServ.Edit()
-		data, err := s.BidsServ.Edit(b, bidId, username)
+		data, err := s.BidsServ.Edit(r.Context(), b, bidId, ifMatch)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchBid) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
 
-func (s *server) handleSumbitBidDecision() http.HandlerFunc {
+func (s *server) handleSubmitBidDecision() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// parse path: bidId
 		bidId := mux.Vars(r)["bidId"]
 		if bidId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
-		// parse querry: descision, username
-		decision := r.URL.Query().Get("decision ")
+		// parse querry: descision
+		decision := r.URL.Query().Get("decision")
 		if decision == "" || (decision != "Approved" && decision != "Rejected") {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-			return
-		}
 		// BidServ.Sumbit()
-		data, err := s.BidsServ.Sumbit(bidId, decision, username)
+		data, err := s.BidsServ.Sumbit(r.Context(), bidId, decision)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchBid) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
@@ -393,45 +294,24 @@ func (s *server) handleBidFeedback() http.HandlerFunc {
 		// parse path: bidId
 		bidId := mux.Vars(r)["bidId"]
 		if bidId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
-		// parse querry: bidFeedback, username
+		// parse querry: bidFeedback
 		bidFeedback := r.URL.Query().Get("bidFeedback")
 		if bidFeedback == "" || len(bidFeedback) > 1000 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-			return
-		}
 		// BidServ.AddFeedback()
-		data, err := s.BidsServ.AddFeedback(bidId, bidFeedback, username)
+		data, err := s.BidsServ.AddFeedback(r.Context(), bidId, bidFeedback)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSucnResource) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
@@ -441,45 +321,23 @@ func (s *server) handleRollbackBid() http.HandlerFunc {
 		// parse path: bidId, version
 		bidId := mux.Vars(r)["bidId"]
 		if bidId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 
 		version, err := strconv.ParseInt(mux.Vars(r)["version"], 10, 32)
 		if err != nil || version < 0 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-			return
-		}
-		// parse querry: username
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 		// BidServ.Rollback()
-		data, err := s.BidsServ.Rollback(bidId, version, username)
+		data, err := s.BidsServ.Rollback(r.Context(), bidId, version)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchBid) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
@@ -489,62 +347,25 @@ func (s *server) handleGetTenderBidsReviews() http.HandlerFunc {
 		// parse path: tenderId
 		tenderId := mux.Vars(r)["tenderId"]
 		if tenderId == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
-		// parse querry: authorUsername, requesterUsername, limit, offset
+		// parse querry: authorUsername, limit, offset
 		authorUsername := r.URL.Query().Get("authorUsername")
 		if authorUsername == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-			return
-		}
-		requesterUsername := r.URL.Query().Get("requesterUsername")
-		if requesterUsername == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 
-		limitStr := r.URL.Query().Get("limit")
-		var limit int64 = 5
-		var err error
-		if len(limitStr) != 0 {
-			limit, err = strconv.ParseInt(limitStr, 10, 32)
-			if err != nil || limit < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		offsetStr := r.URL.Query().Get("offset")
-		var offset int64 = 5
-		if len(offsetStr) != 0 {
-			offset, err = strconv.ParseInt(offsetStr, 10, 32)
-			if err != nil || offset < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
 		}
 		// BidServ.GetReviews()
-		data, err := s.BidsServ.GetReviews(tenderId, authorUsername, requesterUsername, limit, offset)
+		data, err := s.BidsServ.GetReviews(r.Context(), tenderId, authorUsername, limit, offset)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSucnResource) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce [data, data, data]
@@ -0,0 +1,269 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/config"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/health"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/metrics"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/openapi"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/ratelimit"
+	attachmentservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/attachment"
+	auditservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/audit"
+	authservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/auth"
+	bidservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/bider"
+	organizationservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/organization"
+	tenderservice "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services/tender"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/pkg/client"
+	"github.com/sirupsen/logrus"
+)
+
+const testIssuerKey = "issuer-key"
+
+// newTestAPI serves the api on memstore seeded with storetest fixture and returns its url for
+// client.New, responses are validated against openapi.yml so the client is checked against
+// specification too. readLimiter limits reads, nil disables limiting.
+func newTestAPI(t *testing.T, readLimiter ratelimit.Limiter) (string, storetest.Fixture) {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	s, f := storetest.NewMemory(t)
+	pl := policy.Default()
+	auth := config.Auth{Secret: "secret", TokenTTL: time.Hour, IssuerKey: testIssuerKey}
+
+	validator, err := openapi.Load("../../openapi.yml", "/api", true)
+	if err != nil {
+		t.Fatalf("load openapi specification: %s", err)
+	}
+
+	srv := newServer(logger,
+		tenderservice.New(s.Tenders, s.Responsibles, s.Evaluations, s.UnitOfWork, pl, logger),
+		bidservice.New(s.Tenders, s.Bids, s.Responsibles, s.Evaluations, s.UnitOfWork, pl, logger),
+		authservice.New(s.Responsibles, auth, logger),
+		auditservice.New(s.Audit, s.Responsibles, pl, logger),
+		attachmentservice.New(s.Tenders, s.Bids, s.Responsibles, s.Attachments, s.Blobs, s.UnitOfWork, 1<<20, pl, logger),
+		organizationservice.New(s.Organizations, s.Employees, auth.Admins, pl, logger),
+		auth.IssuerKey, readLimiter, nil, metrics.New(logger), health.New(nil, health.Options{}, logger), validator,
+	)
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(httpSrv.Close)
+
+	return httpSrv.URL + "/api", f
+}
+
+// login returns client acting as employee with username
+func login(t *testing.T, api *client.Client, username string) *client.Client {
+	t.Helper()
+
+	token, err := api.IssueToken(context.Background(), username, testIssuerKey)
+	if err != nil {
+		t.Fatalf("IssueToken(%s): %s", username, err)
+	}
+	return api.WithToken(token.Token)
+}
+
+func expectAPIError(t *testing.T, method string, err, class error, code string) *client.Error {
+	t.Helper()
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, class) || apiErr.Code != code {
+		t.Fatalf("%s: expected %q error with code %s, got %v", method, class, code, err)
+	}
+	return apiErr
+}
+
+func TestClientAuth(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, nil)
+	api := client.New(url, client.Options{})
+
+	err := api.Ping(ctx)
+	if err != nil {
+		t.Fatalf("Ping: %s", err)
+	}
+
+	_, err = api.IssueToken(ctx, f.Responsible.Username, "")
+	expectAPIError(t, "IssueToken", err, client.ErrUnauthorized, client.CodeInvalidCredentials)
+	_, err = api.IssueToken(ctx, f.Responsible.Username, "wrong-key")
+	expectAPIError(t, "IssueToken", err, client.ErrUnauthorized, client.CodeInvalidCredentials)
+
+	_, err = api.ListMyTenders(ctx, client.Page{})
+	expectAPIError(t, "ListMyTenders", err, client.ErrUnauthorized, client.CodeMissingToken)
+	_, err = api.WithToken("forged").ListMyTenders(ctx, client.Page{})
+	expectAPIError(t, "ListMyTenders", err, client.ErrUnauthorized, client.CodeInvalidToken)
+
+	_, err = login(t, api, f.Responsible.Username).ListMyTenders(ctx, client.Page{})
+	if err != nil {
+		t.Fatalf("ListMyTenders: %s", err)
+	}
+}
+
+func TestClientTenders(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, nil)
+	api := client.New(url, client.Options{})
+	responsible := login(t, api, f.Responsible.Username)
+
+	tender, err := responsible.CreateTender(ctx, client.NewTender{Name: "Delivery", Description: "Delivery of goods", ServiceType: client.ServiceDelivery, OrganizationId: f.OrgId})
+	if err != nil || tender.Status != client.TenderCreated || tender.Version != 1 {
+		t.Fatalf("CreateTender: expected created tender of version 1, got %+v, %v", tender, err)
+	}
+
+	_, err = responsible.EditTender(ctx, tender.Id, client.TenderEdit{Name: "Stale"}, 2)
+	apiErr := expectAPIError(t, "EditTender", err, client.ErrPreconditionFailed, client.CodeVersionMismatch)
+	if apiErr.CurrentVersion == nil || *apiErr.CurrentVersion != 1 {
+		t.Fatalf("EditTender: expected current version 1, got %v", apiErr.CurrentVersion)
+	}
+
+	tender, err = responsible.UpdateTenderStatus(ctx, tender.Id, client.TenderPublished, 1)
+	if err != nil || tender.Status != client.TenderPublished || tender.Version != 2 {
+		t.Fatalf("UpdateTenderStatus: expected published tender of version 2, got %+v, %v", tender, err)
+	}
+
+	competitor := login(t, api, f.Competitor.Username)
+	_, err = competitor.EditTender(ctx, tender.Id, client.TenderEdit{Name: "Hijacked"}, client.AnyVersion)
+	expectAPIError(t, "EditTender", err, client.ErrForbidden, client.CodeForbidden)
+	status, err := competitor.GetTenderStatus(ctx, tender.Id)
+	if err != nil || status != client.TenderPublished {
+		t.Fatalf("GetTenderStatus: expected published tender seen by other organization, got %s, %v", status, err)
+	}
+
+	tender, err = responsible.RollbackTender(ctx, tender.Id, 1)
+	if err != nil || tender.Status != client.TenderCreated || tender.Version != 3 {
+		t.Fatalf("RollbackTender: expected created tender of version 3, got %+v, %v", tender, err)
+	}
+
+	_, err = responsible.GetTenderStatus(ctx, "00000000-0000-0000-0000-000000000000")
+	expectAPIError(t, "GetTenderStatus", err, client.ErrNotFound, client.CodeTenderNotFound)
+	_, err = responsible.ListMyTenders(ctx, client.Page{Limit: client.MaxLimit + 1})
+	apiErr = expectAPIError(t, "ListMyTenders", err, client.ErrBadRequest, client.CodeInvalidQueryParameters)
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "limit" {
+		t.Fatalf("ListMyTenders: expected invalid limit, got %+v", apiErr.Fields)
+	}
+}
+
+func TestClientAll(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, nil)
+	api := client.New(url, client.Options{})
+	responsible := login(t, api, f.Responsible.Username)
+
+	count := client.MaxLimit + 3
+	for i := 0; i < count; i++ {
+		_, err := responsible.CreateTender(ctx, client.NewTender{Name: fmt.Sprintf("Tender %02d", i), Description: "Tender", ServiceType: client.ServiceConstruction, OrganizationId: f.OrgId})
+		if err != nil {
+			t.Fatalf("CreateTender: %s", err)
+		}
+	}
+
+	tenders, err := client.All(ctx, responsible.ListMyTenders)
+	if err != nil || len(tenders) != count {
+		t.Fatalf("All: expected %d tenders, got %d, %v", count, len(tenders), err)
+	}
+	seen := make(map[string]bool)
+	for _, tender := range tenders {
+		seen[tender.Id] = true
+	}
+	if len(seen) != count {
+		t.Fatalf("All: expected distinct tenders across pages, got %d", len(seen))
+	}
+}
+
+func TestClientBidDecided(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, nil)
+	api := client.New(url, client.Options{})
+	responsible := login(t, api, f.Responsible.Username)
+	competitor := login(t, api, f.Competitor.Username)
+
+	tender, err := responsible.CreateTender(ctx, client.NewTender{Name: "Manufacture", Description: "Parts", ServiceType: client.ServiceManufacture, OrganizationId: f.OrgId})
+	if err != nil {
+		t.Fatalf("CreateTender: %s", err)
+	}
+	_, err = responsible.UpdateTenderStatus(ctx, tender.Id, client.TenderPublished, client.AnyVersion)
+	if err != nil {
+		t.Fatalf("UpdateTenderStatus: %s", err)
+	}
+
+	bid, err := competitor.CreateBid(ctx, client.NewBid{Name: "Offer", Description: "Parts in a week", TenderId: tender.Id, AuthorType: client.AuthorOrganization, AuthorId: f.OtherOrgId})
+	if err != nil || bid.Status != client.BidCreated {
+		t.Fatalf("CreateBid: expected created bid, got %+v, %v", bid, err)
+	}
+
+	_, err = responsible.SubmitDecision(ctx, bid.Id, client.DecisionRejected)
+	expectAPIError(t, "SubmitDecision", err, client.ErrBadRequest, client.CodeDecisionNotAllowed)
+
+	bid, err = competitor.UpdateBidStatus(ctx, bid.Id, client.BidPublished, bid.Version)
+	if err != nil || bid.Status != client.BidPublished {
+		t.Fatalf("UpdateBidStatus: expected published bid, got %+v, %v", bid, err)
+	}
+
+	bid, err = responsible.SubmitDecision(ctx, bid.Id, client.DecisionRejected)
+	if err != nil || bid.Status != client.DecisionRejected {
+		t.Fatalf("SubmitDecision: expected rejected bid, got %+v, %v", bid, err)
+	}
+
+	_, err = competitor.EditBid(ctx, bid.Id, client.BidEdit{Name: "Better offer"}, bid.Version)
+	expectAPIError(t, "EditBid", err, client.ErrConflict, client.CodeBidDecided)
+	_, err = competitor.UpdateBidStatus(ctx, bid.Id, client.BidCanceled, client.AnyVersion)
+	expectAPIError(t, "UpdateBidStatus", err, client.ErrConflict, client.CodeBidDecided)
+	_, err = competitor.RollbackBid(ctx, bid.Id, 1)
+	expectAPIError(t, "RollbackBid", err, client.ErrConflict, client.CodeBidDecided)
+}
+
+func TestClientRateLimit(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, ratelimit.NewMemory(ratelimit.Limit{Rate: 10, Burst: 1}))
+	api := client.New(url, client.Options{})
+	responsible := login(t, api, f.Responsible.Username)
+
+	_, err := responsible.ListMyTenders(ctx, client.Page{})
+	if err != nil {
+		t.Fatalf("ListMyTenders: %s", err)
+	}
+	_, err = responsible.ListMyTenders(ctx, client.Page{})
+	apiErr := expectAPIError(t, "ListMyTenders", err, client.ErrTooManyRequests, client.CodeTooManyRequests)
+	if apiErr.RetryAfter != time.Second {
+		t.Fatalf("ListMyTenders: expected retry after 1s, got %s", apiErr.RetryAfter)
+	}
+
+	// client waits Retry-After of the api instead of its own backoff
+	token, err := api.IssueToken(ctx, f.Responsible.Username, testIssuerKey)
+	if err != nil {
+		t.Fatalf("IssueToken: %s", err)
+	}
+	retrying := client.New(url, client.Options{Token: token.Token, Retries: 1, MinBackoff: time.Millisecond})
+	_, err = retrying.ListMyTenders(ctx, client.Page{})
+	if err != nil {
+		t.Fatalf("ListMyTenders: expected success after retry, got %s", err)
+	}
+}
+
+func TestClientRateLimitAuthFailures(t *testing.T) {
+	ctx := context.Background()
+	url, f := newTestAPI(t, ratelimit.NewMemory(ratelimit.Limit{Rate: 0.1, Burst: 1}))
+
+	// failed authentication takes token of address, rejections beyond it are rate limited
+	guessing := client.New(url, client.Options{Token: "guess"})
+	_, err := guessing.ListMyTenders(ctx, client.Page{})
+	expectAPIError(t, "ListMyTenders", err, client.ErrUnauthorized, client.CodeInvalidToken)
+	_, err = guessing.ListMyTenders(ctx, client.Page{})
+	expectAPIError(t, "ListMyTenders", err, client.ErrTooManyRequests, client.CodeTooManyRequests)
+
+	// authenticated user has own bucket regardless of address
+	responsible := login(t, client.New(url, client.Options{}), f.Responsible.Username)
+	_, err = responsible.ListMyTenders(ctx, client.Page{})
+	if err != nil {
+		t.Fatalf("ListMyTenders: %s", err)
+	}
+}
@@ -3,17 +3,20 @@ package apiserver
 import "errors"
 
 var (
-	ErrInternalDbError     = errors.New("valid ending of operation is unable")
+	ErrInternalDbError     = errors.New("internal error, operation isn't completed")
 	ErrInvalidCredentials  = errors.New("invalid login")
 	ErrHashingPassword     = errors.New("unable to hash password")
-	ErrPanicHanding        = errors.New("internal server troubles")
+	ErrPanicHanding        = errors.New("internal server error")
 	ErrMissingToken        = errors.New("missing authorization token")
-	ErrExpiredToken        = errors.New("token epired")
-	ErrNotAuntificated     = errors.New("user is not auntificated")
-	ErrInvalidToken        = errors.New("invalid acess token")
-	ErrUnsupportedMethod   = errors.New("this method doesn't supported")
-	ErrInvalidQuerryParams = errors.New("invalid or not existing querry parameters")
+	ErrExpiredToken        = errors.New("access token expired")
+	ErrNotAuntificated     = errors.New("user is not authenticated")
+	ErrInvalidToken        = errors.New("invalid access token")
+	ErrUnsupportedMethod   = errors.New("method isn't supported")
+	ErrInvalidQuerryParams = errors.New("invalid or missing query parameters")
 	ErrInvalidRequestBody  = errors.New("invalid request body")
 	ErrServiceUnavailable  = errors.New("service currently is not available")
-	ErrNoSuchResorce       = errors.New("resorce doesn't exist")
+	ErrNoSuchResorce       = errors.New("resource doesn't exist")
+	ErrInvalidIfMatch      = errors.New("invalid If-Match header")
+	ErrMissingFile         = errors.New("request has no file part")
+	ErrTooManyRequests     = errors.New("too many requests, retry later")
 )
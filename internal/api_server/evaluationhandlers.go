@@ -0,0 +1,149 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/gorilla/mux"
+)
+
+// Evaluation endpoints
+
+func (s *server) handleSetTenderCriteria() http.HandlerFunc {
+	type request struct {
+		Name   string `json:"name"`
+		Weight int64  `json:"weight"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := []request{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct
+		criteria := make([]*models.Criterion, 0, len(req))
+		for _, c := range req {
+			criteria = append(criteria, &models.Criterion{
+				Name:   c.Name,
+				Weight: c.Weight,
+			})
+		}
+
+		// validate
+		err = models.ValidateCriteria(criteria)
+		if err != nil {
+			if errors.Is(err, models.ErrCriteriaWeights) || errors.Is(err, models.ErrCriteriaDuplicate) {
+				s.error(w, r, err)
+				return
+			}
+			s.error(w, r, invalidBody(err))
+			return
+		}
+
+		// parse path: tenderId
+		tenderId := mux.Vars(r)["tenderId"]
+		if tenderId == "" || len(tenderId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// TendersServ.SetCriteria()
+		data, err := s.TendersServ.SetCriteria(r.Context(), tenderId, criteria)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetTenderCriteria() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: tenderId
+		tenderId := mux.Vars(r)["tenderId"]
+		if tenderId == "" || len(tenderId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// TendersServ.GetCriteria()
+		data, err := s.TendersServ.GetCriteria(r.Context(), tenderId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleScoreBid() http.HandlerFunc {
+	type request struct {
+		CriterionId string `json:"criterionId"`
+		Value       int64  `json:"value"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := []request{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || len(req) == 0 {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct and validate
+		scores := make([]*models.Score, 0, len(req))
+		for _, sc := range req {
+			score := &models.Score{
+				CriterionId: sc.CriterionId,
+				Value:       sc.Value,
+			}
+			err = score.Validate()
+			if err != nil {
+				s.error(w, r, invalidBody(err))
+				return
+			}
+			scores = append(scores, score)
+		}
+
+		// parse path: bidId
+		bidId := mux.Vars(r)["bidId"]
+		if bidId == "" || len(bidId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// BidsServ.Score()
+		data, err := s.BidsServ.Score(r.Context(), bidId, scores)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetBidScore() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: bidId
+		bidId := mux.Vars(r)["bidId"]
+		if bidId == "" || len(bidId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// BidsServ.GetScore()
+		data, err := s.BidsServ.GetScore(r.Context(), bidId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
@@ -1,11 +1,48 @@
 package apiserver
 
 import (
+	"io"
 	"net/http"
+	"time"
 )
 
 func (s *server) handlePing() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		s.respond(w, r, http.StatusOK, "ok")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "ok")
+	})
+}
+
+// handleLive answers while process is able to serve http, it doesn't depend on database
+func (s *server) handleLive() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, r, http.StatusOK, map[string]string{"status": "alive"})
+	})
+}
+
+// handleReady reports whether the api serves requests and reason of degradation otherwise
+func (s *server) handleReady() http.HandlerFunc {
+	type response struct {
+		Status string    `json:"status"`
+		Reason string    `json:"reason,omitempty"`
+		Since  time.Time `json:"since"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := s.health.Status()
+		if !status.Ready {
+			s.respond(w, r, http.StatusServiceUnavailable, response{
+				Status: "unavailable",
+				Reason: status.Reason.Error(),
+				Since:  status.Since,
+			})
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, response{
+			Status: "ready",
+			Since:  status.Since,
+		})
 	})
 }
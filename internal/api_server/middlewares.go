@@ -1,11 +1,20 @@
 package apiserver
 
 import (
-	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/openapi"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,7 +24,7 @@ func (s *server) setRequestID(next http.Handler) http.Handler {
 		id := uuid.New().String()
 		w.Header().Set("X-Request-Id", id)
 
-		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKeyRequestID, id)))
+		next.ServeHTTP(w, r.WithContext(reqctx.WithRequestID(r.Context(), id)))
 	})
 }
 
@@ -24,7 +33,7 @@ func (s *server) logRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		logger := s.logger.WithFields(logrus.Fields{
 			"remout_addr": r.RemoteAddr,
-			"request_id":  r.Context().Value(ctxKeyRequestID),
+			"request_id":  reqctx.RequestID(r.Context()),
 		})
 		logger.Infof("started %s %s", r.Method, r.RequestURI)
 
@@ -42,11 +51,31 @@ func (s *server) logRequest(next http.Handler) http.Handler {
 	})
 }
 
-func (s *server) deadChecker(next http.Handler) http.Handler {
+// measureRequest records request in metrics under template of matched route
+func (s *server) measureRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !s.available.is {
-			s.error(w, r, http.StatusInternalServerError, s.available.reason)
-			s.logger.Errorf("service is dead reason: %", s.available.reason)
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		start := time.Now()
+		rw := &responseWriter{w, http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		s.metrics.ObserveRequest(route, r.Method, rw.code, time.Since(start))
+	})
+}
+
+// checkReady rejects requests while the api is unavailable, it serves them again
+// as soon as health monitor sees dependencies recovered
+func (s *server) checkReady(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := s.health.Status(); !status.Ready {
+			s.error(w, r, fmt.Errorf("%w: %s", ErrServiceUnavailable, status.Reason))
 			return
 		}
 
@@ -61,17 +90,156 @@ func (s *server) recoverPanic(next http.Handler) http.Handler {
 			if err := recover(); err != nil {
 				logger := s.logger.WithFields(logrus.Fields{
 					"remout_addr": r.RemoteAddr,
-					"request_id":  r.Context().Value(ctxKeyRequestID),
+					"request_id":  reqctx.RequestID(r.Context()),
 					"method":      r.Method,
 					"URI":         r.RequestURI,
 				})
 
 				logger.Errorf("ended hadling by panic with error: %s", err)
 
-				s.error(w, r, http.StatusInternalServerError, ErrPanicHanding)
+				s.error(w, r, ErrPanicHanding)
 			}
 		}()
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// authenticateUser resolves employee by bearer token and puts him into request context
+func (s *server) authenticateUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || token == "" {
+			s.rejectCredentials(w, r, ErrMissingToken)
+			return
+		}
+
+		user, err := s.AuthServ.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrTokenExpired) {
+				s.rejectCredentials(w, r, err)
+				return
+			}
+			s.error(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(reqctx.WithUser(r.Context(), user)))
+	})
+}
+
+// rejectCredentials answers request failed authentication, it takes token from bucket
+// of remote address so guessing tokens is limited like requests to public endpoints
+func (s *server) rejectCredentials(w http.ResponseWriter, r *http.Request, err error) {
+	if !s.allow(w, r, addrKey(r)) {
+		return
+	}
+	s.error(w, r, err)
+}
+
+// actOnBehalf puts organization chosen by organizationId query parameter into request context,
+// users responsible for several organizations choose one of them this way
+func (s *server) actOnBehalf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgId := r.URL.Query().Get("organizationId")
+		if orgId == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(reqctx.WithOrganization(r.Context(), orgId)))
+	})
+}
+
+// limitRate takes token from bucket of authenticated user or of remote address for public endpoints,
+// reads and mutations have separate buckets
+func (s *server) limitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := addrKey(r)
+		if user, ok := reqctx.User(r.Context()); ok {
+			key = "user:" + user.Id
+		}
+		if !s.allow(w, r, key) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes token of request group from bucket of key and sets rate limit headers,
+// request is answered with ErrTooManyRequests when bucket is empty
+func (s *server) allow(w http.ResponseWriter, r *http.Request, key string) bool {
+	limiter, group := s.mutationLimiter, "mutation"
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		limiter, group = s.readLimiter, "read"
+	}
+	if limiter == nil {
+		return true
+	}
+
+	decision := limiter.Allow(group + ":" + key)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+	if !decision.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds(decision.RetryAfter)))
+		s.error(w, r, ErrTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// addrKey is bucket key of remote address, port is dropped as every connection has its own
+func addrKey(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return "addr:" + host
+	}
+	return "addr:" + r.RemoteAddr
+}
+
+// validateSpec rejects requests violating openapi specification. In strict mode responses
+// are held and checked too, ones violating specification are replaced by internal error.
+func (s *server) validateSpec(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		input, err := s.validator.ValidateRequest(r)
+		var reqErr *openapi.RequestError
+		switch {
+		case errors.As(err, &reqErr):
+			s.error(w, r, specViolation(reqErr))
+			return
+		case errors.Is(err, openapi.ErrUnknownRoute):
+			if s.validator.ValidatesResponses() {
+				s.error(w, r, err)
+				return
+			}
+			s.logger.WithField("request_id", reqctx.RequestID(r.Context())).Warnf("%s", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.validator.ValidatesResponses() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := newBufferedWriter()
+		next.ServeHTTP(bw, r)
+
+		header := w.Header().Clone()
+		for key, values := range bw.header {
+			header[key] = values
+		}
+		err = s.validator.ValidateResponse(r.Context(), input, bw.code, header, bw.body.Bytes())
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		bw.flush(w)
+	})
+}
+
+// seconds rounds d up to whole seconds as rate limit headers require
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
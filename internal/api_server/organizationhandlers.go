@@ -0,0 +1,380 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/gorilla/mux"
+)
+
+// Organization endpoints
+
+func (s *server) handleCreateOrganization() http.HandlerFunc {
+	type request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct and validate
+		org := &models.Organization{
+			Name:        req.Name,
+			Description: req.Description,
+			Type:        req.Type,
+		}
+		err = org.Validate()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
+		}
+
+		// OrganizationsServ.CreateOrganization()
+		data, err := s.OrganizationsServ.CreateOrganization(r.Context(), org)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetOrganizations() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+
+		// OrganizationsServ.ListOrganizations()
+		data, err := s.OrganizationsServ.ListOrganizations(r.Context(), limit, offset)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetOrganization() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.GetOrganization()
+		data, err := s.OrganizationsServ.GetOrganization(r.Context(), orgId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleEditOrganization() http.HandlerFunc {
+	type request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct and validate
+		org := &models.Organization{
+			Name:        req.Name,
+			Description: req.Description,
+			Type:        req.Type,
+		}
+		err = org.ValidateEdition()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
+		}
+
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.EditOrganization()
+		data, err := s.OrganizationsServ.EditOrganization(r.Context(), org, orgId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleDeleteOrganization() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.DeleteOrganization()
+		err := s.OrganizationsServ.DeleteOrganization(r.Context(), orgId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce no content
+		s.respond(w, r, http.StatusNoContent, nil)
+	})
+}
+
+func (s *server) handleGetResponsibles() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.ListResponsibles()
+		data, err := s.OrganizationsServ.ListResponsibles(r.Context(), orgId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGrantResponsible() http.HandlerFunc {
+	type request struct {
+		Role string `json:"role"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		// parse path: employeeId
+		userId := mux.Vars(r)["employeeId"]
+		if userId == "" || len(userId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// body is optional, empty one grants default role
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// OrganizationsServ.Grant()
+		err = s.OrganizationsServ.Grant(r.Context(), orgId, userId, req.Role)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce no content
+		s.respond(w, r, http.StatusNoContent, nil)
+	})
+}
+
+func (s *server) handleRevokeResponsible() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: organizationId
+		orgId := mux.Vars(r)["organizationId"]
+		if orgId == "" || len(orgId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		// parse path: employeeId
+		userId := mux.Vars(r)["employeeId"]
+		if userId == "" || len(userId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.Revoke()
+		err := s.OrganizationsServ.Revoke(r.Context(), orgId, userId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce no content
+		s.respond(w, r, http.StatusNoContent, nil)
+	})
+}
+
+// Employee endpoints
+
+func (s *server) handleCreateEmployee() http.HandlerFunc {
+	type request struct {
+		Username  string `json:"username"`
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct and validate
+		emp := &models.Employee{
+			Username:  req.Username,
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+		}
+		err = emp.Validate()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
+		}
+
+		// OrganizationsServ.CreateEmployee()
+		data, err := s.OrganizationsServ.CreateEmployee(r.Context(), emp)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetEmployees() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+
+		// OrganizationsServ.ListEmployees()
+		data, err := s.OrganizationsServ.ListEmployees(r.Context(), limit, offset)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetEmployee() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: employeeId
+		userId := mux.Vars(r)["employeeId"]
+		if userId == "" || len(userId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.GetEmployee()
+		data, err := s.OrganizationsServ.GetEmployee(r.Context(), userId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleEditEmployee() http.HandlerFunc {
+	type request struct {
+		Username  string `json:"username"`
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			s.error(w, r, ErrInvalidRequestBody)
+			return
+		}
+
+		// get into model struct and validate, username can't be changed
+		emp := &models.Employee{
+			Username:  req.Username,
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+		}
+		err = emp.ValidateEdition()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
+		}
+
+		// parse path: employeeId
+		userId := mux.Vars(r)["employeeId"]
+		if userId == "" || len(userId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.EditEmployee()
+		data, err := s.OrganizationsServ.EditEmployee(r.Context(), emp, userId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleDeleteEmployee() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: employeeId
+		userId := mux.Vars(r)["employeeId"]
+		if userId == "" || len(userId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// OrganizationsServ.DeleteEmployee()
+		err := s.OrganizationsServ.DeleteEmployee(r.Context(), userId)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce no content
+		s.respond(w, r, http.StatusNoContent, nil)
+	})
+}
@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultLimit = 5
+	maxLimit     = 50
+)
+
+// pagination parses limit and offset query parameters, limit defaults to 5 and can't exceed 50,
+// offset defaults to 0
+func pagination(r *http.Request) (limit, offset int64, err error) {
+	fields := map[string]string{}
+
+	limit = defaultLimit
+	if str := r.URL.Query().Get("limit"); str != "" {
+		limit, err = strconv.ParseInt(str, 10, 32)
+		switch {
+		case err != nil:
+			fields["limit"] = "must be an integer"
+		case limit < 0 || limit > maxLimit:
+			fields["limit"] = "must be between 0 and " + strconv.Itoa(maxLimit)
+		}
+	}
+
+	if str := r.URL.Query().Get("offset"); str != "" {
+		offset, err = strconv.ParseInt(str, 10, 32)
+		switch {
+		case err != nil:
+			fields["offset"] = "must be an integer"
+		case offset < 0:
+			fields["offset"] = "must be no less than 0"
+		}
+	}
+
+	if len(fields) != 0 {
+		return 0, 0, invalidParams(fields)
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,243 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/openapi"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// errorKind is class of errors answered with the same status and stable code
+type errorKind struct {
+	err    error
+	status int
+	code   string
+	title  string
+}
+
+// statusClientClosedRequest is non-standard status of requests cancelled by client
+const statusClientClosedRequest = 499
+
+// errorKinds is registry of errors known to clients, the first kind err matches wins
+var errorKinds = []errorKind{
+	// Request errors
+	{ErrInvalidQuerryParams, http.StatusBadRequest, "invalid_query_parameters", "Invalid query parameters"},
+	{ErrInvalidRequestBody, http.StatusBadRequest, "invalid_request_body", "Invalid request body"},
+	{ErrInvalidIfMatch, http.StatusBadRequest, "invalid_if_match", "Invalid If-Match header"},
+	{ErrMissingFile, http.StatusBadRequest, "missing_file", "Missing file"},
+	{ErrUnsupportedMethod, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed"},
+	{ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests", "Too many requests"},
+	{ErrNoSuchResorce, http.StatusNotFound, "not_found", "Resource not found"},
+	{models.ErrCriteriaWeights, http.StatusBadRequest, "invalid_criteria_weights", "Invalid criteria weights"},
+	{models.ErrCriteriaDuplicate, http.StatusBadRequest, "duplicate_criteria", "Duplicate criteria"},
+	// Authentication errors
+	{ErrMissingToken, http.StatusUnauthorized, "missing_token", "Missing access token"},
+	{ErrInvalidToken, http.StatusUnauthorized, "invalid_token", "Invalid access token"},
+	{services.ErrInvalidToken, http.StatusUnauthorized, "invalid_token", "Invalid access token"},
+	{ErrExpiredToken, http.StatusUnauthorized, "token_expired", "Access token expired"},
+	{services.ErrTokenExpired, http.StatusUnauthorized, "token_expired", "Access token expired"},
+	{ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", "Invalid credentials"},
+	{services.ErrNoSuchUser, http.StatusUnauthorized, "invalid_credentials", "Invalid credentials"},
+	{services.ErrNotAuthenticated, http.StatusUnauthorized, "not_authenticated", "Not authenticated"},
+	// Access errors
+	{services.ErrNoPermitions, http.StatusForbidden, "forbidden", "Forbidden"},
+	{services.ErrDeadlinePassed, http.StatusForbidden, "deadline_passed", "Tender deadline passed"},
+	// Missing resources
+	{services.ErrNoSuchTender, http.StatusNotFound, "tender_not_found", "Tender not found"},
+	{services.ErrNoSuchBid, http.StatusNotFound, "bid_not_found", "Bid not found"},
+	{services.ErrNoSuchAttachment, http.StatusNotFound, "attachment_not_found", "Attachment not found"},
+	{services.ErrNoSuchOrganization, http.StatusNotFound, "organization_not_found", "Organization not found"},
+	{services.ErrNoSuchEmployee, http.StatusNotFound, "employee_not_found", "Employee not found"},
+	{services.ErrNotResponsible, http.StatusNotFound, "responsible_not_found", "Responsible not found"},
+	{services.ErrNoSucnResource, http.StatusNotFound, "not_found", "Resource not found"},
+	{store.ErrReferenceNotFound, http.StatusNotFound, "reference_not_found", "Referenced resource not found"},
+	// Business rules
+	{services.ErrDecisionNotAllowed, http.StatusBadRequest, "decision_not_allowed", "Decision not allowed"},
+	{services.ErrEvaluationNotAllowed, http.StatusBadRequest, "evaluation_not_allowed", "Evaluation not allowed"},
+	{services.ErrNoCriteria, http.StatusBadRequest, "no_criteria", "Tender has no criteria"},
+	{services.ErrNoSuchCriterion, http.StatusBadRequest, "unknown_criterion", "Unknown criterion"},
+	{services.ErrOrganizationRequired, http.StatusBadRequest, "organization_required", "Organization required"},
+	{services.ErrUnknownRole, http.StatusBadRequest, "unknown_role", "Unknown role"},
+	{services.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge, "attachment_too_large", "Attachment too large"},
+	// Conflicts
+	{services.ErrVersionMismatch, http.StatusPreconditionFailed, "version_mismatch", "Version mismatch"},
+	{services.ErrVersionConflict, http.StatusConflict, "version_conflict", "Version conflict"},
+	{services.ErrBidDecided, http.StatusConflict, "bid_decided", "Bid already decided"},
+	{services.ErrTenderClosed, http.StatusConflict, "tender_closed", "Tender closed"},
+	{services.ErrUserExists, http.StatusConflict, "employee_exists", "Employee already exists"},
+	{services.ErrResourceInUse, http.StatusConflict, "resource_in_use", "Resource in use"},
+	{store.ErrRetryable, http.StatusConflict, "concurrent_update", "Concurrent update"},
+	// Server errors
+	{ErrServiceUnavailable, http.StatusServiceUnavailable, "service_unavailable", "Service unavailable"},
+	{services.ErrServiceDatabaseDisconnected, http.StatusServiceUnavailable, "service_unavailable", "Service unavailable"},
+	{services.ErrServiceTimeout, http.StatusGatewayTimeout, "timeout", "Timeout"},
+	{store.ErrTimeout, http.StatusGatewayTimeout, "timeout", "Timeout"},
+	// client went away and won't read the answer, it isn't failure of the api
+	{services.ErrRequestCanceled, statusClientClosedRequest, "request_canceled", "Request canceled"},
+	{openapi.ErrUnknownRoute, http.StatusInternalServerError, "undocumented_route", "Route isn't documented"},
+	{openapi.ErrInvalidResponse, http.StatusInternalServerError, "invalid_response", "Response doesn't match specification"},
+	{ErrPanicHanding, http.StatusInternalServerError, "internal_error", "Internal server error"},
+	{ErrInternalDbError, http.StatusInternalServerError, "internal_error", "Internal server error"},
+}
+
+// opaqueErrors are kinds wrapping errors of database driver, health checks or specification
+// validator, wrapped text names tables, constraints and hosts, so clients get text of kind only
+var opaqueErrors = []error{
+	store.ErrReferenceNotFound,
+	store.ErrRetryable,
+	store.ErrTimeout,
+	ErrServiceUnavailable,
+	openapi.ErrInvalidResponse,
+}
+
+// internalError answers errors missing in registry, their text isn't shown to clients
+var internalError = errorKind{ErrInternalDbError, http.StatusInternalServerError, "internal_error", "Internal server error"}
+
+// problem is RFC 7807 response body
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	Code      string `json:"code"`
+	RequestId string `json:"requestId,omitempty"`
+	// Reason repeats Detail for clients of former {"reason": ...} responses
+	Reason string `json:"reason"`
+	// Errors lists invalid parameters and fields of request body
+	Errors []fieldError `json:"errors,omitempty"`
+	// CurrentVersion is version of record on version mismatch and conflict
+	CurrentVersion *int64 `json:"currentVersion,omitempty"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is ErrInvalidRequestBody or ErrInvalidQuerryParams listing invalid fields
+type ValidationError struct {
+	// Err is kind of error, ErrInvalidRequestBody when nil
+	Err    error
+	Fields []fieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %d invalid fields", e.Unwrap(), len(e.Fields))
+}
+
+func (e *ValidationError) Unwrap() error {
+	if e.Err == nil {
+		return ErrInvalidRequestBody
+	}
+	return e.Err
+}
+
+// invalidBody turns error of model validation into ValidationError,
+// errors other than ozzo-validation ones become plain ErrInvalidRequestBody
+func invalidBody(err error) error {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return ErrInvalidRequestBody
+	}
+	return invalidFields(errs)
+}
+
+// invalidFields builds ValidationError of field errors keyed by json field name
+func invalidFields(errs map[string]error) *ValidationError {
+	fields := make([]fieldError, 0, len(errs))
+	for field, err := range errs {
+		fields = append(fields, fieldError{Field: field, Message: err.Error()})
+	}
+	return &ValidationError{Fields: sortFields(fields)}
+}
+
+// invalidParams builds ValidationError of query parameter messages keyed by parameter name
+func invalidParams(messages map[string]string) *ValidationError {
+	fields := make([]fieldError, 0, len(messages))
+	for field, message := range messages {
+		fields = append(fields, fieldError{Field: field, Message: message})
+	}
+	return &ValidationError{Err: ErrInvalidQuerryParams, Fields: sortFields(fields)}
+}
+
+func sortFields(fields []fieldError) []fieldError {
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+	return fields
+}
+
+// specViolation turns violations of openapi specification into ValidationError,
+// request with invalid body is ErrInvalidRequestBody and ErrInvalidQuerryParams otherwise
+func specViolation(err *openapi.RequestError) *ValidationError {
+	fields := make([]fieldError, 0, len(err.Fields))
+	for _, field := range err.Fields {
+		fields = append(fields, fieldError{Field: field.Field, Message: field.Message})
+	}
+	if err.InBody {
+		return &ValidationError{Err: ErrInvalidRequestBody, Fields: fields}
+	}
+	return &ValidationError{Err: ErrInvalidQuerryParams, Fields: fields}
+}
+
+// kindOf finds registered kind of err
+func kindOf(err error) (errorKind, bool) {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.err) {
+			return kind, true
+		}
+	}
+	return internalError, false
+}
+
+// error responds with problem of err kind, lost database connection also marks the api unavailable
+func (s *server) error(w http.ResponseWriter, r *http.Request, err error) {
+	kind, known := kindOf(err)
+	p := problem{
+		Type:      "urn:tenderer:problem:" + kind.code,
+		Title:     kind.title,
+		Status:    kind.status,
+		Detail:    err.Error(),
+		Instance:  r.URL.Path,
+		Code:      kind.code,
+		RequestId: reqctx.RequestID(r.Context()),
+	}
+	if !known {
+		s.logger.WithField("request_id", p.RequestId).Errorf("unexpected error: %s on %s %s", err, r.Method, r.URL.Path)
+		p.Detail = ErrInternalDbError.Error()
+	}
+	if known && slices.Contains(opaqueErrors, kind.err) {
+		s.logger.WithField("request_id", p.RequestId).Warnf("%s: %s on %s %s", kind.code, err, r.Method, r.URL.Path)
+		p.Detail = kind.err.Error()
+	}
+	p.Reason = p.Detail
+
+	if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
+		s.unavailableOnError(err)
+	}
+
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		p.Errors = valErr.Fields
+	}
+
+	var verErr *services.VersionError
+	if errors.As(err, &verErr) {
+		w.Header().Set("ETag", etag(verErr.Current))
+		p.CurrentVersion = &verErr.Current
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
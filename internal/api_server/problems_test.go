@@ -0,0 +1,171 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+func TestError(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &server{logger: logger}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{
+			name:   "service error",
+			err:    services.ErrNoSuchTender,
+			status: http.StatusNotFound,
+			code:   "tender_not_found",
+			detail: services.ErrNoSuchTender.Error(),
+		},
+		{
+			name:   "wrapped service error",
+			err:    fmt.Errorf("get condition: %w", services.ErrDeadlinePassed),
+			status: http.StatusForbidden,
+			code:   "deadline_passed",
+			detail: "get condition: " + services.ErrDeadlinePassed.Error(),
+		},
+		{
+			name:   "first matching kind wins",
+			err:    fmt.Errorf("%w: %w", ErrMissingToken, services.ErrNoPermitions),
+			status: http.StatusUnauthorized,
+			code:   "missing_token",
+		},
+		{
+			name:   "driver error",
+			err:    fmt.Errorf("%w: %s", store.ErrReferenceNotFound, `pq: insert or update on table "bids" violates foreign key constraint "bids_tender_id_fkey"`),
+			status: http.StatusNotFound,
+			code:   "reference_not_found",
+			detail: store.ErrReferenceNotFound.Error(),
+		},
+		{
+			name:   "retryable driver error",
+			err:    fmt.Errorf("%w: %s", store.ErrRetryable, "pq: deadlock detected"),
+			status: http.StatusConflict,
+			code:   "concurrent_update",
+			detail: store.ErrRetryable.Error(),
+		},
+		{
+			name:   "statement timeout",
+			err:    fmt.Errorf("%w: %s", store.ErrTimeout, "pq: canceling statement due to statement timeout"),
+			status: http.StatusGatewayTimeout,
+			code:   "timeout",
+			detail: store.ErrTimeout.Error(),
+		},
+		{
+			name:   "service timeout",
+			err:    services.ErrServiceTimeout,
+			status: http.StatusGatewayTimeout,
+			code:   "timeout",
+		},
+		{
+			name:   "request canceled",
+			err:    services.ErrRequestCanceled,
+			status: statusClientClosedRequest,
+			code:   "request_canceled",
+		},
+		{
+			name:   "unknown error",
+			err:    errors.New(`pq: relation "tenders" does not exist`),
+			status: http.StatusInternalServerError,
+			code:   "internal_error",
+			detail: ErrInternalDbError.Error(),
+		},
+		{
+			name:   "bid decided",
+			err:    services.ErrBidDecided,
+			status: http.StatusConflict,
+			code:   "bid_decided",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, rec := respondError(t, s, tt.err)
+
+			if rec.Code != tt.status || p.Status != tt.status {
+				t.Fatalf("expected status %d, got %d and %d in body", tt.status, rec.Code, p.Status)
+			}
+			if p.Code != tt.code || p.Type != "urn:tenderer:problem:"+tt.code {
+				t.Fatalf("expected code %s, got %s of type %s", tt.code, p.Code, p.Type)
+			}
+			if tt.detail != "" && p.Detail != tt.detail {
+				t.Fatalf("expected detail %q, got %q", tt.detail, p.Detail)
+			}
+			if strings.Contains(p.Detail, "pq:") {
+				t.Fatalf("detail shows driver error: %q", p.Detail)
+			}
+			if p.Reason != p.Detail {
+				t.Fatalf("expected reason to repeat detail, got %q", p.Reason)
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Fatalf("expected problem content type, got %s", contentType)
+			}
+		})
+	}
+}
+
+func TestErrorValidation(t *testing.T) {
+	s := &server{logger: logrus.New()}
+
+	err := invalidParams(map[string]string{"offset": "must be non negative", "limit": "must be at most 50"})
+	p, rec := respondError(t, s, err)
+
+	if rec.Code != http.StatusBadRequest || p.Code != "invalid_query_parameters" {
+		t.Fatalf("expected 400 invalid_query_parameters, got %d %s", rec.Code, p.Code)
+	}
+	if len(p.Errors) != 2 || p.Errors[0].Field != "limit" || p.Errors[1].Field != "offset" {
+		t.Fatalf("expected errors of limit and offset, got %+v", p.Errors)
+	}
+
+	p, _ = respondError(t, s, &ValidationError{Fields: []fieldError{{Field: "name", Message: "cannot be blank"}}})
+	if p.Code != "invalid_request_body" || len(p.Errors) != 1 {
+		t.Fatalf("expected invalid_request_body with one field, got %s %+v", p.Code, p.Errors)
+	}
+}
+
+func TestErrorVersion(t *testing.T) {
+	s := &server{logger: logrus.New()}
+
+	err := &services.VersionError{Err: services.ErrVersionMismatch, Current: 7}
+	p, rec := respondError(t, s, err)
+
+	if rec.Code != http.StatusPreconditionFailed || p.Code != "version_mismatch" {
+		t.Fatalf("expected 412 version_mismatch, got %d %s", rec.Code, p.Code)
+	}
+	if p.CurrentVersion == nil || *p.CurrentVersion != 7 {
+		t.Fatalf("expected current version 7, got %v", p.CurrentVersion)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"7"` {
+		t.Fatalf("expected ETag of current version, got %s", etag)
+	}
+}
+
+func respondError(t *testing.T, s *server, err error) (*problem, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.error(rec, httptest.NewRequest(http.MethodGet, "/api/tenders/my", nil), err)
+
+	p := &problem{}
+	decodeErr := json.NewDecoder(rec.Body).Decode(p)
+	if decodeErr != nil {
+		t.Fatalf("decode problem: %s", decodeErr)
+	}
+	return p, rec
+}
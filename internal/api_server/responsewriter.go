@@ -1,13 +1,48 @@
 package apiserver
 
-import "net/http"
+import (
+	"bytes"
+	"net/http"
+)
 
 type responseWriter struct {
 	http.ResponseWriter
 	code int
 }
 
-func (w *responseWriter) WrightHeader(statusCode int) {
+func (w *responseWriter) WriteHeader(statusCode int) {
 	w.code = statusCode
 	w.ResponseWriter.WriteHeader((statusCode))
 }
+
+// bufferedWriter holds response until it's checked, then it's written by flush
+type bufferedWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newBufferedWriter() *bufferedWriter {
+	return &bufferedWriter{header: http.Header{}, code: http.StatusOK}
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(statusCode int) {
+	w.code = statusCode
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// flush writes held response to w
+func (w *bufferedWriter) flush(dst http.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	dst.WriteHeader(w.code)
+	dst.Write(w.body.Bytes())
+}
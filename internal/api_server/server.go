@@ -4,45 +4,68 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/health"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/metrics"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/openapi"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/ratelimit"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	// sessionName        = "Authorization"
-	ctxKeyUser ctxKey = iota
-	ctxKeyRequestID
-)
-
-type ctxKey int8
-
-type availability struct {
-	is     bool
-	reason error
-}
-
 type server struct {
 	router *mux.Router
 	logger *logrus.Logger
 
 	TendersServ services.Tenders
 	BidsServ    services.Bids
+	AuthServ    services.Auth
+	AuditServ   services.Audit
+
+	AttachmentsServ   services.Attachments
+	OrganizationsServ services.Organizations
+
+	// issuerKey guards token issuing endpoint, tokens are issued only to its holders
+	issuerKey string
 
-	available availability
+	// readLimiter and mutationLimiter limit request rate of clients, nil disables limiting
+	readLimiter     ratelimit.Limiter
+	mutationLimiter ratelimit.Limiter
+
+	// metrics of requests, nil disables them
+	metrics *metrics.Metrics
+
+	// health holds readiness of the api
+	health *health.Monitor
+
+	// validator checks requests against openapi specification, nil disables validation
+	validator *openapi.Validator
 }
 
-func newServer(logger *logrus.Logger, TendersServ services.Tenders, BidsServ services.Bids) *server {
+func newServer(logger *logrus.Logger, TendersServ services.Tenders, BidsServ services.Bids, AuthServ services.Auth, AuditServ services.Audit, AttachmentsServ services.Attachments, OrganizationsServ services.Organizations, issuerKey string, readLimiter, mutationLimiter ratelimit.Limiter, metrics *metrics.Metrics, health *health.Monitor, validator *openapi.Validator) *server {
 	srv := &server{
 		router: mux.NewRouter(),
 		logger: logger,
 
 		TendersServ: TendersServ,
 		BidsServ:    BidsServ,
+		AuthServ:    AuthServ,
+		AuditServ:   AuditServ,
+
+		AttachmentsServ:   AttachmentsServ,
+		OrganizationsServ: OrganizationsServ,
+
+		issuerKey: issuerKey,
+
+		readLimiter:     readLimiter,
+		mutationLimiter: mutationLimiter,
 
-		available: availability{
-			is: true,
-		},
+		metrics: metrics,
+
+		health: health,
+
+		validator: validator,
 	}
 
 	srv.configureRouter()
@@ -50,9 +73,9 @@ func newServer(logger *logrus.Logger, TendersServ services.Tenders, BidsServ ser
 	return srv
 }
 
-func (s *server) DeadOnError(err error) {
-	s.available.is = false
-	s.available.reason = err
+// unavailableOnError marks the api unavailable until health monitor sees database back
+func (s *server) unavailableOnError(err error) {
+	s.health.Fail(err)
 }
 
 func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -60,40 +83,100 @@ func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *server) configureRouter() {
-	s.router = s.router.PathPrefix("/api").Subrouter()
+	// Metrics endpoint
+	if s.metrics != nil {
+		s.router.Handle("/metrics", s.metrics.Handler()).Methods("GET")
+	}
 
-	s.router.Use(s.setRequestID)
-	s.router.Use(s.deadChecker)
-	s.router.Use(s.logRequest)
-	s.router.Use(s.recoverPanic)
+	// Probes endpoints
+	s.router.HandleFunc("/health/live", s.handleLive()).Methods("GET")
+	s.router.HandleFunc("/health/ready", s.handleReady()).Methods("GET")
+
+	api := s.router.PathPrefix("/api").Subrouter()
+
+	api.Use(s.setRequestID)
+	if s.metrics != nil {
+		api.Use(s.measureRequest)
+	}
+	api.Use(s.checkReady)
+	api.Use(s.logRequest)
+	api.Use(s.recoverPanic)
+
+	// Public endpoints
+	public := api.NewRoute().Subrouter()
+	public.Use(s.limitRate)
+	if s.validator != nil {
+		public.Use(s.validateSpec)
+	}
+	public.HandleFunc("/ping", s.handlePing()).Methods("GET")
+	public.HandleFunc("/auth/token", s.handleIssueToken()).Methods("POST")
+
+	private := api.NewRoute().Subrouter()
+	private.Use(s.authenticateUser)
+	private.Use(s.limitRate)
+	private.Use(s.actOnBehalf)
+	if s.validator != nil {
+		private.Use(s.validateSpec)
+	}
 
 	// Tenders endpoints
-	s.router.HandleFunc("/ping", s.handlePing()).Methods("GET")
-	s.router.HandleFunc("/tenders", s.handleGetTendersList()).Methods("GET")
-	s.router.HandleFunc("/tenders/new", s.handleCreateTender()).Methods("POST")
-	s.router.HandleFunc("/tenders/my", s.handleGetUsersTenders()).Methods("GET")
-	s.router.HandleFunc("/tenders/{tenderId}/status", s.handleInterractTenderStatus()).Methods("GET", "PUT")
-	s.router.HandleFunc("/tenders/{tenderId}/edit", s.handleEditTender()).Methods("PATCH")
-	s.router.HandleFunc("/tenders/{tenderId}/rollback/{version}", s.handleRollbackTender()).Methods("PUT")
+	private.HandleFunc("/tenders", s.handleGetTendersList()).Methods("GET")
+	private.HandleFunc("/tenders/new", s.handleCreateTender()).Methods("POST")
+	private.HandleFunc("/tenders/my", s.handleGetUsersTenders()).Methods("GET")
+	private.HandleFunc("/tenders/{tenderId}/status", s.handleInterractTenderStatus()).Methods("GET", "PUT")
+	private.HandleFunc("/tenders/{tenderId}/edit", s.handleEditTender()).Methods("PATCH")
+	private.HandleFunc("/tenders/{tenderId}/rollback/{version}", s.handleRollbackTender()).Methods("PUT")
+	private.HandleFunc("/tenders/{tenderId}/versions", s.handleGetTenderVersions()).Methods("GET")
+	private.HandleFunc("/tenders/{tenderId}/versions/{from}/diff/{to}", s.handleDiffTenderVersions()).Methods("GET")
 	// Bids endpoints
-	s.router.HandleFunc("/bids/new", s.handleCreateBid()).Methods("POST")
-	s.router.HandleFunc("/bids/my", s.handleGetUsersBids()).Methods("GET")
-	s.router.HandleFunc("/bids/{tenderId}/list", s.handleGetTendersBids()).Methods("GET")
-	s.router.HandleFunc("/bids/{bidId}/status", s.handleInterractBidStatus()).Methods("GET", "PUT")
-	s.router.HandleFunc("/bids/{bidId}/edit", s.handleEditBid()).Methods("PATCH")
-	s.router.HandleFunc("/bids/{bidId}/sumbit_decision", s.handleSumbitBidDecision()).Methods("PUT")
-	s.router.HandleFunc("/bids/{bidId}/feedback", s.handleBidFeedback()).Methods("PUT")
-	s.router.HandleFunc("/bids/{bidId}/rallback/{version}", s.handleRollbackBid()).Methods("PUT")
-	s.router.HandleFunc("/bids/{tenderId}/reviews", s.handleGetTenderBidsReviews()).Methods("GET")
-}
-
-// Func for making call of respond func with Error pattern
-func (s *server) error(w http.ResponseWriter, r *http.Request, code int, err error) {
-	s.respond(w, r, code, map[string]string{"reason": err.Error()})
+	private.HandleFunc("/bids/new", s.handleCreateBid()).Methods("POST")
+	private.HandleFunc("/bids/my", s.handleGetUsersBids()).Methods("GET")
+	private.HandleFunc("/bids/{tenderId}/list", s.handleGetTendersBids()).Methods("GET")
+	private.HandleFunc("/tenders/{tenderId}/ranking", s.handleGetTenderRanking()).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/status", s.handleInterractBidStatus()).Methods("GET", "PUT")
+	private.HandleFunc("/bids/{bidId}/edit", s.handleEditBid()).Methods("PATCH")
+	private.HandleFunc("/bids/{bidId}/submit_decision", s.handleSubmitBidDecision()).Methods("PUT")
+	private.HandleFunc("/bids/{bidId}/feedback", s.handleBidFeedback()).Methods("PUT")
+	private.HandleFunc("/bids/{bidId}/rollback/{version}", s.handleRollbackBid()).Methods("PUT")
+	private.HandleFunc("/bids/{tenderId}/reviews", s.handleGetTenderBidsReviews()).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/versions", s.handleGetBidVersions()).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/versions/{from}/diff/{to}", s.handleDiffBidVersions()).Methods("GET")
+	// Evaluation endpoints
+	private.HandleFunc("/tenders/{tenderId}/criteria", s.handleSetTenderCriteria()).Methods("PUT")
+	private.HandleFunc("/tenders/{tenderId}/criteria", s.handleGetTenderCriteria()).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/scores", s.handleScoreBid()).Methods("PUT")
+	private.HandleFunc("/bids/{bidId}/score", s.handleGetBidScore()).Methods("GET")
+	// Attachment endpoints
+	private.HandleFunc("/tenders/{tenderId}/attachments", s.handleAddAttachment(models.AttachmentTender, "tenderId")).Methods("POST")
+	private.HandleFunc("/tenders/{tenderId}/attachments", s.handleGetAttachments(models.AttachmentTender, "tenderId")).Methods("GET")
+	private.HandleFunc("/tenders/{tenderId}/attachments/{attachmentId}", s.handleDownloadAttachment(models.AttachmentTender, "tenderId")).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/attachments", s.handleAddAttachment(models.AttachmentBid, "bidId")).Methods("POST")
+	private.HandleFunc("/bids/{bidId}/attachments", s.handleGetAttachments(models.AttachmentBid, "bidId")).Methods("GET")
+	private.HandleFunc("/bids/{bidId}/attachments/{attachmentId}", s.handleDownloadAttachment(models.AttachmentBid, "bidId")).Methods("GET")
+	// Organization endpoints
+	private.HandleFunc("/organizations", s.handleCreateOrganization()).Methods("POST")
+	private.HandleFunc("/organizations", s.handleGetOrganizations()).Methods("GET")
+	private.HandleFunc("/organizations/{organizationId}", s.handleGetOrganization()).Methods("GET")
+	private.HandleFunc("/organizations/{organizationId}", s.handleEditOrganization()).Methods("PATCH")
+	private.HandleFunc("/organizations/{organizationId}", s.handleDeleteOrganization()).Methods("DELETE")
+	private.HandleFunc("/organizations/{organizationId}/responsibles", s.handleGetResponsibles()).Methods("GET")
+	private.HandleFunc("/organizations/{organizationId}/responsibles/{employeeId}", s.handleGrantResponsible()).Methods("PUT")
+	private.HandleFunc("/organizations/{organizationId}/responsibles/{employeeId}", s.handleRevokeResponsible()).Methods("DELETE")
+	// Employee endpoints
+	private.HandleFunc("/employees", s.handleCreateEmployee()).Methods("POST")
+	private.HandleFunc("/employees", s.handleGetEmployees()).Methods("GET")
+	private.HandleFunc("/employees/{employeeId}", s.handleGetEmployee()).Methods("GET")
+	private.HandleFunc("/employees/{employeeId}", s.handleEditEmployee()).Methods("PATCH")
+	private.HandleFunc("/employees/{employeeId}", s.handleDeleteEmployee()).Methods("DELETE")
+	// Audit endpoints
+	private.HandleFunc("/organizations/{organizationId}/audit", s.handleGetAuditLog()).Methods("GET")
 }
 
 // Universal func for sending any type of respond (Error, Responde, etc.)
 func (s *server) respond(w http.ResponseWriter, r *http.Request, code int, data interface{}) {
+	if data != nil {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	w.WriteHeader(code)
 	if data != nil {
 		json.NewEncoder(w).Encode(data)
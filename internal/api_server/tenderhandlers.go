@@ -2,13 +2,12 @@ package apiserver
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
-	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
-	validation "github.com/go-ozzo/ozzo-validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/gorilla/mux"
 )
 
@@ -17,25 +16,10 @@ import (
 func (s *server) handleGetTendersList() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// parse querry: limit, offset, service_type
-		limitStr := r.URL.Query().Get("limit")
-		var limit int64 = 5
-		var err error
-		if len(limitStr) != 0 {
-			limit, err = strconv.ParseInt(limitStr, 10, 32)
-			if err != nil || limit < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		offsetStr := r.URL.Query().Get("offset")
-		var offset int64 = 5
-		if len(offsetStr) != 0 {
-			offset, err = strconv.ParseInt(offsetStr, 10, 32)
-			if err != nil || offset < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
 		}
 
 		serviceTypes := r.URL.Query()["service_type"]
@@ -44,21 +28,16 @@ func (s *server) handleGetTendersList() http.HandlerFunc {
 				validation.Each(validation.In("Construction", "Delivery", "Manufacture")),
 			)
 			if err != nil {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
 		}
 
 		// Tenders.List()
 
-		data, err := s.TendersServ.List(limit, offset, serviceTypes)
+		data, err := s.TendersServ.List(r.Context(), limit, offset, serviceTypes)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 
@@ -69,17 +48,17 @@ func (s *server) handleGetTendersList() http.HandlerFunc {
 
 func (s *server) handleCreateTender() http.HandlerFunc {
 	type request struct {
-		Name     string `json:"name"`
-		Descr    string `json:"description"`
-		ServType string `json:"serviceType"`
-		OrgId    string `json:"organizationId"`
-		Username string `json:"creatorUsername"`
+		Name     string     `json:"name"`
+		Descr    string     `json:"description"`
+		ServType string     `json:"serviceType"`
+		Deadline *time.Time `json:"deadline"`
+		OrgId    string     `json:"organizationId"`
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		req := &request{}
 		err := json.NewDecoder(r.Body).Decode(req)
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, ErrInvalidRequestBody)
 			return
 		}
 
@@ -88,87 +67,47 @@ func (s *server) handleCreateTender() http.HandlerFunc {
 			Name:        req.Name,
 			Description: req.Descr,
 			ServType:    req.ServType,
+			Deadline:    req.Deadline,
 		}
 
 		// validate
 		err = t.Validate()
-		if err != nil || len(req.OrgId) > 100 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
-			return
+		if err == nil {
+			err = validation.Errors{
+				"organizationId": validation.Validate(req.OrgId, validation.Length(0, 100)),
+			}.Filter()
 		}
-
-		ru := &models.Responsible{
-			OrgId:    req.OrgId,
-			Username: req.Username,
+		if err != nil {
+			s.error(w, r, invalidBody(err))
+			return
 		}
 
 		// TendersServ.Create()
-		data, err := s.TendersServ.Create(t, ru)
+		data, err := s.TendersServ.Create(r.Context(), t, req.OrgId)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
 
 func (s *server) handleGetUsersTenders() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// parse querry: limit, offset, username
-		limitStr := r.URL.Query().Get("limit")
-		var limit int64 = 5
-		var err error
-		if len(limitStr) != 0 {
-			limit, err = strconv.ParseInt(limitStr, 10, 32)
-			if err != nil || limit < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		offsetStr := r.URL.Query().Get("offset")
-		var offset int64 = 5
-		if len(offsetStr) != 0 {
-			offset, err = strconv.ParseInt(offsetStr, 10, 32)
-			if err != nil || offset < 0 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-		}
-
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusUnauthorized, ErrInvalidQuerryParams)
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
 			return
 		}
+
 		// TenserServ.GetByName()
-		data, err := s.TendersServ.GetByName(limit, offset, username)
+		data, err := s.TendersServ.GetByName(r.Context(), limit, offset)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce [data, data, data]
@@ -182,36 +121,13 @@ func (s *server) handleInterractTenderStatus() http.HandlerFunc {
 			// parse path: tenderId
 			tenderId := mux.Vars(r)["tenderId"]
 			if tenderId == "" || len(tenderId) > 100 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-				return
-			}
-			// parse querry: username
-			username := r.URL.Query().Get("username")
-			if username == "" {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
 			// TenderServ.GetStat()
-			data, err := s.TendersServ.GetStat(tenderId, username)
+			data, err := s.TendersServ.GetStat(r.Context(), tenderId)
 			if err != nil {
-				if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-					s.DeadOnError(err)
-					s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchUser) {
-					s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-					return
-				}
-				if errors.Is(err, services.ErrNoPermitions) {
-					s.error(w, r, http.StatusForbidden, err)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchTender) {
-					s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-					return
-				}
-				s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+				s.error(w, r, err)
 				return
 			}
 			// response status
@@ -220,63 +136,50 @@ func (s *server) handleInterractTenderStatus() http.HandlerFunc {
 			// parse path: tenderId
 			tenderId := mux.Vars(r)["tenderId"]
 			if tenderId == "" || len(tenderId) > 100 {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
-			// parse querry: status, username
+			// parse querry: status
 			status := r.URL.Query().Get("status")
 			if status == "" || (status != "Created" && status != "Published" && status != "Closed") {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+				s.error(w, r, ErrInvalidQuerryParams)
 				return
 			}
 
-			username := r.URL.Query().Get("username")
-			if username == "" {
-				s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			// parse header: If-Match
+			ifMatch, err := parseIfMatch(r)
+			if err != nil {
+				s.error(w, r, ErrInvalidIfMatch)
 				return
 			}
+
 			// TenderServ.ChangeStat()
-			data, err := s.TendersServ.ChangeStat(tenderId, status, username)
+			data, err := s.TendersServ.ChangeStat(r.Context(), tenderId, status, ifMatch)
 			if err != nil {
-				if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-					s.DeadOnError(err)
-					s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchUser) {
-					s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-					return
-				}
-				if errors.Is(err, services.ErrNoPermitions) {
-					s.error(w, r, http.StatusForbidden, err)
-					return
-				}
-				if errors.Is(err, services.ErrNoSuchTender) {
-					s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-					return
-				}
-				s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+				s.error(w, r, err)
 				return
 			}
 			// response data
+			w.Header().Set("ETag", etag(data.Version))
 			s.respond(w, r, http.StatusOK, data)
 		} else {
-			s.error(w, r, http.StatusMethodNotAllowed, ErrUnsupportedMethod)
+			s.error(w, r, ErrUnsupportedMethod)
 		}
 	})
 }
 
 func (s *server) handleEditTender() http.HandlerFunc {
 	type request struct {
-		Name     string `json:"name"`
-		Descr    string `json:"description"`
-		ServType string `json:"serviceType"`
+		Name     string     `json:"name"`
+		Descr    string     `json:"description"`
+		ServType string     `json:"serviceType"`
+		Deadline *time.Time `json:"deadline"`
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		req := &request{}
 		err := json.NewDecoder(r.Body).Decode(req)
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, ErrInvalidRequestBody)
 			return
 		}
 
@@ -285,51 +188,37 @@ func (s *server) handleEditTender() http.HandlerFunc {
 			Name:        req.Name,
 			Description: req.Descr,
 			ServType:    req.ServType,
+			Deadline:    req.Deadline,
 		}
 
 		// parse path: tenderId
 		tenderId := mux.Vars(r)["tenderId"]
 		if tenderId == "" || len(tenderId) > 100 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
-		// parse querry: username
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+
+		err = t.ValidateEdition()
+		if err != nil {
+			s.error(w, r, invalidBody(err))
 			return
 		}
 
-		err = t.ValidateEdition()
+		// parse header: If-Match
+		ifMatch, err := parseIfMatch(r)
 		if err != nil {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidRequestBody)
+			s.error(w, r, ErrInvalidIfMatch)
 			return
 		}
 
 		// TenderServ.Edit()
-		data, err := s.TendersServ.Edit(t, tenderId, username)
+		data, err := s.TendersServ.Edit(r.Context(), t, tenderId, ifMatch)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchTender) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
@@ -339,44 +228,22 @@ func (s *server) handleRollbackTender() http.HandlerFunc {
 		// parse path: tenderId, version
 		tenderId := mux.Vars(r)["tenderId"]
 		if tenderId == "" || len(tenderId) > 100 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 		version, err := strconv.ParseInt(mux.Vars(r)["version"], 10, 32)
 		if err != nil || version < 1 {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
-			return
-		}
-		// parse querry: username
-		username := r.URL.Query().Get("username")
-		if username == "" {
-			s.error(w, r, http.StatusBadRequest, ErrInvalidQuerryParams)
+			s.error(w, r, ErrInvalidQuerryParams)
 			return
 		}
 		// Tender.Rollback()
-		data, err := s.TendersServ.Rollback(tenderId, username, version)
+		data, err := s.TendersServ.Rollback(r.Context(), tenderId, version)
 		if err != nil {
-			if errors.Is(err, services.ErrServiceDatabaseDisconnected) {
-				s.DeadOnError(err)
-				s.error(w, r, http.StatusInternalServerError, ErrServiceUnavailable)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchUser) {
-				s.error(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
-				return
-			}
-			if errors.Is(err, services.ErrNoPermitions) {
-				s.error(w, r, http.StatusForbidden, err)
-				return
-			}
-			if errors.Is(err, services.ErrNoSuchTender) {
-				s.error(w, r, http.StatusNotFound, ErrNoSuchResorce)
-				return
-			}
-			s.error(w, r, http.StatusInternalServerError, ErrInternalDbError)
+			s.error(w, r, err)
 			return
 		}
 		// responce data
+		w.Header().Set("ETag", etag(data.Version))
 		s.respond(w, r, http.StatusOK, data)
 	})
 }
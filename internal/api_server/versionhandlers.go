@@ -0,0 +1,124 @@
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Version history endpoints
+
+func (s *server) handleGetTenderVersions() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: tenderId
+		tenderId := mux.Vars(r)["tenderId"]
+		if tenderId == "" || len(tenderId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+
+		// TendersServ.GetVersions()
+		data, err := s.TendersServ.GetVersions(r.Context(), tenderId, limit, offset)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleDiffTenderVersions() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: tenderId, from, to
+		tenderId := mux.Vars(r)["tenderId"]
+		if tenderId == "" || len(tenderId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		from, err := strconv.ParseInt(mux.Vars(r)["from"], 10, 32)
+		if err != nil || from < 1 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		to, err := strconv.ParseInt(mux.Vars(r)["to"], 10, 32)
+		if err != nil || to < 1 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// TendersServ.Diff()
+		data, err := s.TendersServ.Diff(r.Context(), tenderId, from, to)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleGetBidVersions() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: bidId
+		bidId := mux.Vars(r)["bidId"]
+		if bidId == "" || len(bidId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// parse querry: limit, offset
+		limit, offset, err := pagination(r)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+
+		// BidsServ.GetVersions()
+		data, err := s.BidsServ.GetVersions(r.Context(), bidId, limit, offset)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce [data, data, data]
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
+
+func (s *server) handleDiffBidVersions() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// parse path: bidId, from, to
+		bidId := mux.Vars(r)["bidId"]
+		if bidId == "" || len(bidId) > 100 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		from, err := strconv.ParseInt(mux.Vars(r)["from"], 10, 32)
+		if err != nil || from < 1 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+		to, err := strconv.ParseInt(mux.Vars(r)["to"], 10, 32)
+		if err != nil || to < 1 {
+			s.error(w, r, ErrInvalidQuerryParams)
+			return
+		}
+
+		// BidsServ.Diff()
+		data, err := s.BidsServ.Diff(r.Context(), bidId, from, to)
+		if err != nil {
+			s.error(w, r, err)
+			return
+		}
+		// responce data
+		s.respond(w, r, http.StatusOK, data)
+	})
+}
@@ -2,21 +2,119 @@ package config
 
 import (
 	"log"
+	"net"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Server struct {
-	Port string
+	Port         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is grace period of draining connections and stopping workers on SIGINT or SIGTERM
+	ShutdownTimeout time.Duration
 }
 
+const (
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
+)
+
 type Database struct {
-	Conn string
+	Backend string
+	Conn    string
+	// Seed is path to json file loaded into memory backend on start
+	Seed string
+	// ReadTimeout and WriteTimeout bound single reading and modifying store operation
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// SlowQuery is duration of query logged as slow one
+	SlowQuery time.Duration
+	// ReplicaConn is optional read replica serving reading operations
+	ReplicaConn string
+	Pool        Pool
+}
+
+// Pool configures connection pool shared by all stores, replica has pool of its own
+// with the same limits. StatementTimeout is enforced by postgres for every statement.
+type Pool struct {
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	ConnMaxIdleTime  time.Duration
+	StatementTimeout time.Duration
+}
+
+type Auth struct {
+	Secret   string
+	TokenTTL time.Duration
+	// IssuerKey is required from callers of token issuing, holder of it gets token of any employee
+	IssuerKey string
+	// Admins are usernames of employees managing organizations and employees
+	Admins []string
+}
+
+type Scheduler struct {
+	// DeadlineInterval is period of closing tenders with passed deadline, zero disables it
+	DeadlineInterval time.Duration
+}
+
+type Attachments struct {
+	// Dir is directory of local blob store used with postgres backend
+	Dir string
+	// MaxSize is maximum size of single file in bytes
+	MaxSize int64
+}
+
+type Policy struct {
+	// File is path to json access policy, empty one means built-in policy
+	File string
+}
+
+// RateLimit configures token buckets of every client, reads are GET and HEAD requests,
+// mutations are all others, zero rate disables limiting of the group
+type RateLimit struct {
+	ReadRate      float64
+	ReadBurst     int
+	MutationRate  float64
+	MutationBurst int
+}
+
+// Health configures checking of database readiness, failed checks are retried
+// with backoff doubling from MinBackoff up to MaxBackoff
+type Health struct {
+	Interval   time.Duration
+	Timeout    time.Duration
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+const (
+	ValidationOff      = "off"
+	ValidationRequests = "requests"
+	ValidationStrict   = "strict"
+)
+
+// OpenAPI configures validation against specification, strict validation also checks
+// responses and is meant for tests
+type OpenAPI struct {
+	Spec       string
+	Validation string
 }
 
 type Config struct {
-	Srv Server
-	Db  Database
+	Srv         Server
+	Db          Database
+	Auth        Auth
+	Scheduler   Scheduler
+	Attachments Attachments
+	Policy      Policy
+	RateLimit   RateLimit
+	Health      Health
+	OpenAPI     OpenAPI
 }
 
 func Load() *Config {
@@ -26,18 +124,139 @@ func Load() *Config {
 		log.Fatal("incorrect server address")
 	}
 
+	readTimeout := getEnvDuration("SERVER_READ_TIMEOUT", "15s")
+	writeTimeout := getEnvDuration("SERVER_WRITE_TIMEOUT", "30s")
+	idleTimeout := getEnvDuration("SERVER_IDLE_TIMEOUT", "60s")
+	shutdownTimeout := getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", "15s")
+
+	tokenTTL, err := time.ParseDuration(getEnvDefault("AUTH_TOKEN_TTL", "24h"))
+	if err != nil {
+		log.Fatal("incorrect token ttl")
+	}
+
+	deadlineInterval, err := time.ParseDuration(getEnvDefault("DEADLINE_CHECK_INTERVAL", "1m"))
+	if err != nil || deadlineInterval < 0 {
+		log.Fatal("incorrect deadline check interval")
+	}
+
+	attachmentMaxSize, err := strconv.ParseInt(getEnvDefault("ATTACHMENT_MAX_SIZE", "10485760"), 10, 64)
+	if err != nil || attachmentMaxSize <= 0 {
+		log.Fatal("incorrect attachment max size")
+	}
+
+	validation := getEnvDefault("OPENAPI_VALIDATION", ValidationRequests)
+	switch validation {
+	case ValidationOff, ValidationRequests, ValidationStrict:
+	default:
+		log.Fatal("incorrect openapi validation mode")
+	}
+
+	readRate, readBurst := getEnvLimit("RATE_LIMIT_READ", "20", "40")
+	mutationRate, mutationBurst := getEnvLimit("RATE_LIMIT_MUTATION", "5", "10")
+
+	db := Database{
+		Backend: getEnvDefault("STORAGE_BACKEND", BackendPostgres),
+		Seed:    getEnvDefault("MEMSTORE_SEED", ""),
+
+		ReadTimeout:  getEnvDuration("DB_READ_TIMEOUT", "5s"),
+		WriteTimeout: getEnvDuration("DB_WRITE_TIMEOUT", "10s"),
+		SlowQuery:    getEnvDuration("DB_SLOW_QUERY", "1s"),
+	}
+	switch db.Backend {
+	case BackendPostgres:
+		db.Conn = getEnv("POSTGRES_CONN")
+		db.ReplicaConn = getEnvDefault("POSTGRES_REPLICA_CONN", "")
+		db.Pool = Pool{
+			MaxOpenConns:     getEnvInt("DB_MAX_OPEN_CONNS", "25"),
+			MaxIdleConns:     getEnvInt("DB_MAX_IDLE_CONNS", "10"),
+			ConnMaxLifetime:  getEnvDuration("DB_CONN_MAX_LIFETIME", "30m"),
+			ConnMaxIdleTime:  getEnvDuration("DB_CONN_MAX_IDLE_TIME", "5m"),
+			StatementTimeout: getEnvDuration("DB_STATEMENT_TIMEOUT", "30s"),
+		}
+	case BackendMemory:
+	default:
+		log.Fatal("incorrect storage backend")
+	}
+
 	config := &Config{
 		Srv: Server{
-			Port: srvAddr[div+1:],
+			Port:            srvAddr[div+1:],
+			ReadTimeout:     readTimeout,
+			WriteTimeout:    writeTimeout,
+			IdleTimeout:     idleTimeout,
+			ShutdownTimeout: shutdownTimeout,
+		},
+		Db: db,
+		Auth: Auth{
+			Secret:    getEnv("AUTH_SECRET"),
+			TokenTTL:  tokenTTL,
+			IssuerKey: getEnv("AUTH_ISSUER_KEY"),
+			Admins:    getEnvList("ADMIN_USERNAMES"),
 		},
-		Db: Database{
-			Conn: getEnv("POSTGRES_CONN"),
+		Scheduler: Scheduler{
+			DeadlineInterval: deadlineInterval,
+		},
+		Attachments: Attachments{
+			Dir:     getEnvDefault("ATTACHMENTS_DIR", "data/attachments"),
+			MaxSize: attachmentMaxSize,
+		},
+		Policy: Policy{
+			File: getEnvDefault("POLICY_FILE", ""),
+		},
+		RateLimit: RateLimit{
+			ReadRate:      readRate,
+			ReadBurst:     readBurst,
+			MutationRate:  mutationRate,
+			MutationBurst: mutationBurst,
+		},
+		Health: Health{
+			Interval:   getEnvDuration("HEALTH_CHECK_INTERVAL", "10s"),
+			Timeout:    getEnvDuration("HEALTH_CHECK_TIMEOUT", "2s"),
+			MinBackoff: getEnvDuration("HEALTH_BACKOFF_MIN", "1s"),
+			MaxBackoff: getEnvDuration("HEALTH_BACKOFF_MAX", "30s"),
+		},
+		OpenAPI: OpenAPI{
+			Spec:       getEnvDefault("OPENAPI_SPEC", "openapi.yml"),
+			Validation: validation,
 		},
 	}
 
 	return config
 }
 
+// Client configures command line tools calling the api, see cmd/tenderctl
+type Client struct {
+	// URL is base url of the api including /api prefix
+	URL string
+	// Username is employee the tool acts as, IssuerKey guards issuing of his token
+	Username  string
+	IssuerKey string
+	Timeout   time.Duration
+}
+
+// LoadClient reads settings of tools from environment of the api, the api is found
+// by SERVER_ADDRESS on local host unless API_URL is set
+func LoadClient() *Client {
+	apiURL := getEnvDefault("API_URL", "")
+	if apiURL == "" {
+		host, port, err := net.SplitHostPort(getEnvDefault("SERVER_ADDRESS", "localhost:8080"))
+		if err != nil {
+			log.Fatal("incorrect server address")
+		}
+		if host == "" || host == "0.0.0.0" {
+			host = "localhost"
+		}
+		apiURL = "http://" + net.JoinHostPort(host, port) + "/api"
+	}
+
+	return &Client{
+		URL:       apiURL,
+		Username:  getEnvDefault("TENDERCTL_USER", ""),
+		IssuerKey: getEnvDefault("AUTH_ISSUER_KEY", ""),
+		Timeout:   getEnvDuration("TENDERCTL_TIMEOUT", "30s"),
+	}
+}
+
 func getEnv(key string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists || value == "" {
@@ -45,3 +264,55 @@ func getEnv(key string) string {
 	}
 	return value
 }
+
+func getEnvDefault(key, def string) string {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return def
+	}
+	return value
+}
+
+// getEnvList splits comma separated value, absent variable gives empty list
+func getEnvList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvDuration reads positive duration like "15s"
+func getEnvDuration(key, def string) time.Duration {
+	value, err := time.ParseDuration(getEnvDefault(key, def))
+	if err != nil || value <= 0 {
+		log.Fatalf("incorrect %s", key)
+	}
+	return value
+}
+
+// getEnvInt reads positive integer
+func getEnvInt(key, def string) int {
+	value, err := strconv.Atoi(getEnvDefault(key, def))
+	if err != nil || value <= 0 {
+		log.Fatalf("incorrect %s", key)
+	}
+	return value
+}
+
+// getEnvLimit reads requests per second from prefix_RPS and bucket size from prefix_BURST
+func getEnvLimit(prefix, defRate, defBurst string) (float64, int) {
+	rate, err := strconv.ParseFloat(getEnvDefault(prefix+"_RPS", defRate), 64)
+	if err != nil || rate < 0 {
+		log.Fatalf("incorrect %s_RPS", prefix)
+	}
+
+	burst, err := strconv.Atoi(getEnvDefault(prefix+"_BURST", defBurst))
+	if err != nil || burst <= 0 {
+		log.Fatalf("incorrect %s_BURST", prefix)
+	}
+	return rate, burst
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Entities files are attached to
+const (
+	AttachmentTender = "Tender"
+	AttachmentBid    = "Bid"
+)
+
+// Attachment describes file attached to tender or bid, its content is kept
+// in blob store under sha256 digest so equal files share one copy
+type Attachment struct {
+	Id          string    `json:"id"`
+	EntityType  string    `json:"-"`
+	EntityId    string    `json:"-"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Digest      string    `json:"sha256"`
+	AuthorId    string    `json:"authorId"`
+	Created     time.Time `json:"createdAt"`
+}
+
+func (a *Attachment) Validate() error {
+	return validation.ValidateStruct(
+		a,
+		validation.Field(&a.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&a.ContentType, validation.Required, validation.Length(1, 100)),
+	)
+}
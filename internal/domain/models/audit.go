@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Audited actions
+const (
+	AuditCreate       = "Create"
+	AuditEdit         = "Edit"
+	AuditChangeStatus = "ChangeStatus"
+	AuditRollback     = "Rollback"
+	AuditFeedback     = "Feedback"
+	AuditDecision     = "Decision"
+	AuditCriteria     = "SetCriteria"
+	AuditScore        = "Score"
+	AuditAttach       = "Attach"
+)
+
+// Audited entities
+const (
+	AuditTender = "Tender"
+	AuditBid    = "Bid"
+)
+
+// AuditEntry records who performed mutation, zero version means there is no such version
+// and empty actor means mutation was performed by the service itself
+type AuditEntry struct {
+	Id         string    `json:"id"`
+	ActorId    string    `json:"actorId,omitempty"`
+	OrgId      string    `json:"organizationId"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType"`
+	EntityId   string    `json:"entityId"`
+	OldVersion int64     `json:"oldVersion,omitempty"`
+	NewVersion int64     `json:"newVersion,omitempty"`
+	RequestId  string    `json:"requestId"`
+	Created    time.Time `json:"createdAt"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"regexp"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -16,8 +17,25 @@ type Bid struct {
 	AuthorId    string    `json:"authorId"`
 	Version     int64     `json:"version"`
 	Created     time.Time `json:"createdAt"`
+	// OrgId is organization the bid is submitted on behalf of, for bids authored by user
+	// it's organization chosen by the author at creation
+	OrgId string `json:"-"`
+	// Amount and Currency are optional but always set together
+	Amount       *float64 `json:"amount,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
+	DeliveryDays *int64   `json:"deliveryDays,omitempty"`
 }
 
+// RankedBid is published bid with its place among bids of the same currency
+// or among all bids when ranked by total score
+type RankedBid struct {
+	Rank  int64    `json:"rank"`
+	Score *float64 `json:"score,omitempty"`
+	*Bid
+}
+
+var currencyCode = regexp.MustCompile("^[A-Z]{3}$")
+
 func (b *Bid) Validate() error {
 	return validation.ValidateStruct(
 		b,
@@ -26,5 +44,19 @@ func (b *Bid) Validate() error {
 		validation.Field(&b.TenderId, validation.Required, validation.Length(36, 100)),
 		validation.Field(&b.AuthorType, validation.Required, validation.In("Organization", "User")),
 		validation.Field(&b.AuthorId, validation.Required, validation.Length(36, 100)),
+		validation.Field(&b.Amount, validation.When(b.Currency != "", validation.Required), validation.Min(0.0).Exclusive()),
+		validation.Field(&b.Currency, validation.When(b.Amount != nil, validation.Required), validation.Match(currencyCode)),
+		validation.Field(&b.DeliveryDays, validation.Min(int64(0))),
+	)
+}
+
+func (b *Bid) ValidateEdition() error {
+	return validation.ValidateStruct(
+		b,
+		validation.Field(&b.Name, validation.Length(1, 100)),
+		validation.Field(&b.Description, validation.Length(1, 1000)),
+		validation.Field(&b.Amount, validation.When(b.Currency != "", validation.Required), validation.Min(0.0).Exclusive()),
+		validation.Field(&b.Currency, validation.When(b.Amount != nil, validation.Required), validation.Match(currencyCode)),
+		validation.Field(&b.DeliveryDays, validation.Min(int64(0))),
 	)
 }
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// FieldChange describes change of single field between two versions
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type VersionsDiff struct {
+	From    int64         `json:"from"`
+	To      int64         `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+func DiffTenders(from, to *Tender) *VersionsDiff {
+	diff := &VersionsDiff{
+		From:    from.Version,
+		To:      to.Version,
+		Changes: []FieldChange{},
+	}
+	diff.compare("name", from.Name, to.Name)
+	diff.compare("description", from.Description, to.Description)
+	diff.compare("status", from.Status, to.Status)
+	diff.compare("serviceType", from.ServType, to.ServType)
+	diff.compare("deadline", formatDeadline(from.Deadline), formatDeadline(to.Deadline))
+	return diff
+}
+
+func DiffBids(from, to *Bid) *VersionsDiff {
+	diff := &VersionsDiff{
+		From:    from.Version,
+		To:      to.Version,
+		Changes: []FieldChange{},
+	}
+	diff.compare("name", from.Name, to.Name)
+	diff.compare("description", from.Description, to.Description)
+	diff.compare("status", from.Status, to.Status)
+	diff.compare("amount", formatAmount(from.Amount), formatAmount(to.Amount))
+	diff.compare("currency", from.Currency, to.Currency)
+	diff.compare("deliveryDays", formatDays(from.DeliveryDays), formatDays(to.DeliveryDays))
+	return diff
+}
+
+func (d *VersionsDiff) compare(field, from, to string) {
+	if from != to {
+		d.Changes = append(d.Changes, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+func formatDeadline(deadline *time.Time) string {
+	if deadline == nil {
+		return ""
+	}
+	return deadline.UTC().Format(time.RFC3339)
+}
+
+func formatAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*amount, 'f', 2, 64)
+}
+
+func formatDays(days *int64) string {
+	if days == nil {
+		return ""
+	}
+	return strconv.FormatInt(*days, 10)
+}
@@ -0,0 +1,30 @@
+package models
+
+import validation "github.com/go-ozzo/ozzo-validation/v4"
+
+type Employee struct {
+	Id        string `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+func (e *Employee) Validate() error {
+	return validation.ValidateStruct(
+		e,
+		validation.Field(&e.Username, validation.Required, validation.Length(1, 50)),
+		validation.Field(&e.FirstName, validation.Length(0, 50)),
+		validation.Field(&e.LastName, validation.Length(0, 50)),
+	)
+}
+
+// ValidateEdition checks fields of partial update, empty fields are left unchanged
+// and username can't be changed
+func (e *Employee) ValidateEdition() error {
+	return validation.ValidateStruct(
+		e,
+		validation.Field(&e.Username, validation.Empty),
+		validation.Field(&e.FirstName, validation.Length(0, 50)),
+		validation.Field(&e.LastName, validation.Length(0, 50)),
+	)
+}
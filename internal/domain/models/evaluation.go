@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	ErrCriteriaWeights   = errors.New("criteria weights must sum up to 100")
+	ErrCriteriaDuplicate = errors.New("criteria names must be unique")
+)
+
+// Criterion is weighted aspect tender bids are evaluated by, weight is percent of total score
+type Criterion struct {
+	Id       string `json:"id"`
+	TenderId string `json:"-"`
+	Name     string `json:"name"`
+	Weight   int64  `json:"weight"`
+}
+
+func (c *Criterion) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&c.Weight, validation.Required, validation.Min(int64(1)), validation.Max(int64(100))),
+	)
+}
+
+// ValidateCriteria checks full set of tender criteria
+func ValidateCriteria(criteria []*Criterion) error {
+	err := validation.Validate(criteria, validation.Required, validation.Length(1, 20))
+	if err != nil {
+		return err
+	}
+
+	var sum int64
+	names := make(map[string]struct{}, len(criteria))
+	for _, c := range criteria {
+		err = c.Validate()
+		if err != nil {
+			return err
+		}
+		if _, ok := names[c.Name]; ok {
+			return ErrCriteriaDuplicate
+		}
+		names[c.Name] = struct{}{}
+		sum += c.Weight
+	}
+	if sum != 100 {
+		return ErrCriteriaWeights
+	}
+	return nil
+}
+
+// Score is mark given to bid by single evaluator against single criterion
+type Score struct {
+	BidId       string    `json:"-"`
+	CriterionId string    `json:"criterionId"`
+	EvaluatorId string    `json:"evaluatorId"`
+	Value       int64     `json:"value"`
+	Created     time.Time `json:"createdAt"`
+}
+
+func (s *Score) Validate() error {
+	return validation.ValidateStruct(
+		s,
+		validation.Field(&s.CriterionId, validation.Required, validation.Length(36, 100)),
+		validation.Field(&s.Value, validation.Min(int64(0)), validation.Max(int64(100))),
+	)
+}
+
+type CriterionScore struct {
+	CriterionId string `json:"criterionId"`
+	Name        string `json:"name"`
+	Weight      int64  `json:"weight"`
+	// Average is mean of evaluators scores, zero if bid isn't scored yet
+	Average     float64 `json:"average"`
+	Evaluations int64   `json:"evaluations"`
+}
+
+// BidScore is weighted total of bid scores in range 0..100
+type BidScore struct {
+	BidId    string           `json:"bidId"`
+	Total    float64          `json:"total"`
+	Criteria []CriterionScore `json:"criteria"`
+}
+
+// ScoreBid computes total score of bid, scores of other bids are ignored
+func ScoreBid(bidId string, criteria []*Criterion, scores []*Score) *BidScore {
+	result := &BidScore{
+		BidId:    bidId,
+		Criteria: make([]CriterionScore, 0, len(criteria)),
+	}
+	for _, c := range criteria {
+		cs := CriterionScore{
+			CriterionId: c.Id,
+			Name:        c.Name,
+			Weight:      c.Weight,
+		}
+		var sum int64
+		for _, s := range scores {
+			if s.BidId == bidId && s.CriterionId == c.Id {
+				sum += s.Value
+				cs.Evaluations++
+			}
+		}
+		if cs.Evaluations != 0 {
+			cs.Average = float64(sum) / float64(cs.Evaluations)
+		}
+		result.Total += cs.Average * float64(c.Weight) / 100
+		result.Criteria = append(result.Criteria, cs)
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Legal forms of organization
+const (
+	OrgTypeIE  = "IE"
+	OrgTypeLLC = "LLC"
+	OrgTypeJSC = "JSC"
+)
+
+type Organization struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type,omitempty"`
+	Created     time.Time `json:"createdAt"`
+}
+
+func (o *Organization) Validate() error {
+	return validation.ValidateStruct(
+		o,
+		validation.Field(&o.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&o.Description, validation.Length(0, 500)),
+		validation.Field(&o.Type, validation.In(OrgTypeIE, OrgTypeLLC, OrgTypeJSC)),
+	)
+}
+
+// ValidateEdition checks fields of partial update, empty fields are left unchanged
+func (o *Organization) ValidateEdition() error {
+	return validation.ValidateStruct(
+		o,
+		validation.Field(&o.Name, validation.Length(1, 100)),
+		validation.Field(&o.Description, validation.Length(0, 500)),
+		validation.Field(&o.Type, validation.In(OrgTypeIE, OrgTypeLLC, OrgTypeJSC)),
+	)
+}
+
+// Member is employee responsible for organization with his role in it
+type Member struct {
+	*Employee
+	Role string `json:"role"`
+}
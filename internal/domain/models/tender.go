@@ -15,6 +15,8 @@ type Tender struct {
 	OrgId       string    `json:"-"`
 	Version     int64     `json:"version"`
 	Created     time.Time `json:"createdAt"`
+	// Deadline is optional, published tender is closed once it passes
+	Deadline *time.Time `json:"deadline,omitempty"`
 }
 
 func (t *Tender) Validate() error {
@@ -23,6 +25,7 @@ func (t *Tender) Validate() error {
 		validation.Field(&t.Name, validation.Required, validation.Length(1, 100)),
 		validation.Field(&t.Description, validation.Required, validation.Length(1, 500)),
 		validation.Field(&t.ServType, validation.Required, validation.In("Construction", "Delivery", "Manufacture")),
+		validation.Field(&t.Deadline, validation.Min(time.Now())),
 		// validation.Field(&t.Status, validation.Required, validation.In("Created", "Published")),
 	)
 }
@@ -36,5 +39,11 @@ func (t *Tender) ValidateEdition() error {
 		validation.Field(&t.Name, validation.Length(1, 100)),
 		validation.Field(&t.Description, validation.Length(1, 500)),
 		validation.Field(&t.ServType, validation.In("Construction", "Delivery", "Manufacture")),
+		validation.Field(&t.Deadline, validation.Min(time.Now())),
 	)
 }
+
+// Expired reports whether tender deadline passed by the moment now
+func (t *Tender) Expired(now time.Time) bool {
+	return t.Deadline != nil && !now.Before(*t.Deadline)
+}
@@ -0,0 +1,143 @@
+// Package health tracks readiness of the api by periodically checking its dependencies
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Check reports error when dependency can't serve requests
+type Check func(ctx context.Context) error
+
+// Options configures checking, failed checks are retried with backoff doubling
+// from MinBackoff up to MaxBackoff until dependency recovers
+type Options struct {
+	Interval   time.Duration
+	Timeout    time.Duration
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Status is snapshot of readiness, Reason and Since describe degradation
+type Status struct {
+	Ready  bool
+	Reason error
+	Since  time.Time
+}
+
+// Monitor holds readiness of the api, it is safe for concurrent use
+type Monitor struct {
+	mu     sync.RWMutex
+	status Status
+
+	check   Check
+	opts    Options
+	recheck chan struct{}
+	logger  *logrus.Entry
+}
+
+// New creates monitor of ready api, nil check means the api has no dependencies to check
+func New(check Check, opts Options, log *logrus.Logger) *Monitor {
+	logger := log.WithFields(logrus.Fields{
+		"component": "health",
+	})
+
+	return &Monitor{
+		status:  Status{Ready: true, Since: time.Now()},
+		check:   check,
+		opts:    opts,
+		recheck: make(chan struct{}, 1),
+		logger:  logger,
+	}
+}
+
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Fail marks the api unavailable with reason, running monitor rechecks dependencies
+// at once and brings the api back when they recover
+func (m *Monitor) Fail(reason error) {
+	if m.set(false, reason) {
+		m.logger.Errorf("service is unavailable reason: %s", reason)
+	}
+
+	select {
+	case m.recheck <- struct{}{}:
+	default:
+	}
+}
+
+// Run checks dependencies every interval while they are healthy and with backoff
+// while they fail until ctx is done, blocks caller
+func (m *Monitor) Run(ctx context.Context) {
+	if m.check == nil {
+		return
+	}
+
+	backoff := m.opts.MinBackoff
+	timer := time.NewTimer(m.opts.Interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.recheck:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+		}
+
+		err := m.probe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case err == nil:
+			if m.set(true, nil) {
+				m.logger.Info("service is available again")
+			}
+			backoff = m.opts.MinBackoff
+			timer.Reset(m.opts.Interval)
+		default:
+			if m.set(false, err) {
+				m.logger.Errorf("service is unavailable reason: %s", err)
+			} else {
+				m.logger.Warnf("service is still unavailable reason: %s, next check in %s", err, backoff)
+			}
+			timer.Reset(backoff)
+			backoff = min(backoff*2, m.opts.MaxBackoff)
+		}
+	}
+}
+
+func (m *Monitor) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+	return m.check(ctx)
+}
+
+// set updates status, it reports whether readiness has changed
+func (m *Monitor) set(ready bool, reason error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := m.status.Ready != ready
+	if changed {
+		m.status.Since = time.Now()
+	}
+	m.status.Ready = ready
+	m.status.Reason = reason
+	return changed
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMonitor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	var calls atomic.Int32
+	check := func(ctx context.Context) error {
+		if calls.Add(1) < 3 {
+			return errors.New("database is down")
+		}
+		return nil
+	}
+	m := New(check, Options{
+		Interval:   time.Hour,
+		Timeout:    time.Second,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 4 * time.Millisecond,
+	}, logger)
+
+	if !m.Status().Ready {
+		t.Fatalf("Status: expected ready monitor before first check")
+	}
+
+	// recheck requested before Run is picked up by its first iteration
+	reason := errors.New("connection lost")
+	m.Fail(reason)
+	status := m.Status()
+	if status.Ready || !errors.Is(status.Reason, reason) {
+		t.Fatalf("Status: expected unavailable with reason, got %+v", status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !m.Status().Ready {
+		if time.Now().After(deadline) {
+			t.Fatalf("Status: expected ready after dependency recovered, got %+v after %d checks", m.Status(), calls.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if calls.Load() != 3 || m.Status().Reason != nil {
+		t.Fatalf("Status: expected ready without reason after 3 checks, got %+v after %d checks", m.Status(), calls.Load())
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run: expected return after ctx is done")
+	}
+}
+
+func TestMonitorWithoutCheck(t *testing.T) {
+	m := New(nil, Options{}, logrus.New())
+	m.Run(context.Background())
+
+	if !m.Status().Ready {
+		t.Fatalf("Status: expected ready monitor without dependencies")
+	}
+}
@@ -0,0 +1,90 @@
+// Package metrics collects api metrics and exposes them in prometheus text format
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "tenderer"
+
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	queries  *prometheus.HistogramVec
+	logger   *logrus.Entry
+}
+
+// New creates metrics registry with go runtime and process collectors
+func New(log *logrus.Logger) *Metrics {
+	logger := log.WithFields(logrus.Fields{
+		"component": "metrics",
+	})
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of handled http requests by route template, method and status code.",
+		}, []string{"route", "method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of http requests by route template, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Latency of database queries by store and its method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"store", "method"}),
+		logger: logger,
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		m.queries,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves all collected metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records handled request, route is template of matched route
+// so identifiers in path don't produce new series
+func (m *Metrics) ObserveRequest(route, method string, code int, elapsed time.Duration) {
+	status := strconv.Itoa(code)
+	m.requests.WithLabelValues(route, method, status).Inc()
+	m.latency.WithLabelValues(route, method, status).Observe(elapsed.Seconds())
+}
+
+// RegisterDB exposes connection pool stats of db labeled by its name
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterStores exposes number of tenders and bids by status, stores are queried on every scrape
+func (m *Metrics) RegisterStores(tenders store.Tenders, bids store.Bids) error {
+	return m.registry.Register(&storesCollector{
+		tenders: tenders,
+		bids:    bids,
+		logger:  m.logger,
+	})
+}
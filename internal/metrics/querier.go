@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// Querier wraps querier of sql stores so every query is timed by store and method it's made from
+func (m *Metrics) Querier(q store.Querier) store.Querier {
+	return &querier{
+		q: q,
+		m: m,
+	}
+}
+
+type querier struct {
+	q store.Querier
+	m *Metrics
+}
+
+func (q *querier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer q.observe(caller(), time.Now())
+	return q.q.ExecContext(ctx, query, args...)
+}
+
+func (q *querier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer q.observe(caller(), time.Now())
+	return q.q.QueryContext(ctx, query, args...)
+}
+
+func (q *querier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer q.observe(caller(), time.Now())
+	return q.q.QueryRowContext(ctx, query, args...)
+}
+
+func (q *querier) observe(pc uintptr, start time.Time) {
+	name := storeMethod(pc)
+	q.m.queries.WithLabelValues(name.store, name.method).Observe(time.Since(start).Seconds())
+}
+
+type methodName struct {
+	store  string
+	method string
+}
+
+// names caches store methods by program counter
+var names sync.Map
+
+// caller returns program counter of function calling querier method
+func caller() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	return pcs[0]
+}
+
+// storeMethod turns function like ".../tenderstore.(*TenderStore).GetStatus.func1"
+// into store "tenderstore" and method "GetStatus"
+func storeMethod(pc uintptr) methodName {
+	if name, ok := names.Load(pc); ok {
+		return name.(methodName)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	function := frame.Function
+	function = function[strings.LastIndex(function, "/")+1:]
+
+	pkg, method, _ := strings.Cut(function, ".")
+	if _, after, found := strings.Cut(method, ")."); found {
+		method = after
+	}
+	method, _, _ = strings.Cut(method, ".")
+
+	name := methodName{store: pkg, method: method}
+	names.Store(pc, name)
+	return name
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	tendersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "tenders"),
+		"Number of tenders by status of their latest version.",
+		[]string{"status"}, nil,
+	)
+	bidsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "bids"),
+		"Number of bids by status of their latest version.",
+		[]string{"status"}, nil,
+	)
+)
+
+// storesCollector reports business gauges counted by stores at scrape time
+type storesCollector struct {
+	tenders store.Tenders
+	bids    store.Bids
+	logger  *logrus.Entry
+}
+
+func (c *storesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tendersDesc
+	ch <- bidsDesc
+}
+
+// Collect skips gauges store failed to count, scrape itself still succeeds
+func (c *storesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	counts, err := c.tenders.CountByStatus(ctx)
+	if err != nil {
+		c.logger.Errorf("unexpected error: %s on method CountByStatus", err)
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(tendersDesc, prometheus.GaugeValue, float64(count), status)
+	}
+
+	counts, err = c.bids.CountByStatus(ctx)
+	if err != nil {
+		c.logger.Errorf("unexpected error: %s on method CountByStatus", err)
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(bidsDesc, prometheus.GaugeValue, float64(count), status)
+	}
+}
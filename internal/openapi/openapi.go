@@ -0,0 +1,224 @@
+// Package openapi validates api requests and responses against openapi specification
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+var (
+	ErrUnknownRoute    = errors.New("route isn't described by specification")
+	ErrInvalidResponse = errors.New("response doesn't match specification")
+)
+
+// FieldError is violation of specification by single parameter or body field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// RequestError lists violations of request, InBody tells body from parameters
+type RequestError struct {
+	InBody bool
+	Fields []FieldError
+}
+
+func (e *RequestError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "request doesn't match specification: " + strings.Join(messages, "; ")
+}
+
+type Validator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+	// uploads are options of operations taking multipart bodies, see ValidateRequest
+	uploads   *openapi3filter.Options
+	responses bool
+}
+
+// Load reads and checks specification, its servers are replaced by prefix so operations
+// are matched by path on any host. Responses are validated only when responses is set,
+// it's meant for tests as whole response is buffered.
+func Load(path, prefix string, responses bool) (*Validator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load: %w", err)
+	}
+	err = doc.Validate(loader.Context)
+	if err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+	doc.Servers = openapi3.Servers{{URL: prefix}}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		MultiError: true,
+		// authentication is checked by the api itself
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+	uploads := *options
+	uploads.ExcludeRequestBody = true
+
+	return &Validator{
+		router:    router,
+		options:   options,
+		uploads:   &uploads,
+		responses: responses,
+	}, nil
+}
+
+// ValidatesResponses reports whether ValidateResponse should be called
+func (v *Validator) ValidatesResponses() bool {
+	return v.responses
+}
+
+// ValidateRequest checks parameters and body of r, body stays readable by handlers.
+// Multipart bodies aren't checked: validation reads them whole into memory, handlers
+// stream them under size limit instead. Violations are returned as *RequestError,
+// requests to routes missing in specification as ErrUnknownRoute. Returned input is
+// passed to ValidateResponse.
+func (v *Validator) ValidateRequest(r *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := v.router.FindRoute(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownRoute, r.Method, r.URL.Path)
+	}
+
+	// handlers decode bodies as json regardless of content type, clients often omit it
+	if r.ContentLength != 0 && r.Header.Get("Content-Type") == "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	options := v.options
+	if takesMultipart(route.Operation) {
+		options = v.uploads
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    options,
+	}
+	err = openapi3filter.ValidateRequest(r.Context(), input)
+	if err != nil {
+		return input, requestError(err)
+	}
+	return input, nil
+}
+
+// ValidateResponse checks status, headers and body written by handler
+func (v *Validator) ValidateResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, status int, header http.Header, body []byte) error {
+	err := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(body)),
+		Options:                v.options,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	return nil
+}
+
+// takesMultipart reports whether operation's body is multipart form
+func takesMultipart(op *openapi3.Operation) bool {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return false
+	}
+	return op.RequestBody.Value.Content.Get("multipart/form-data") != nil
+}
+
+// requestError collects violations reported by openapi3filter
+func requestError(err error) *RequestError {
+	result := &RequestError{}
+	for _, err := range flatten(err) {
+		var reqErr *openapi3filter.RequestError
+		if !errors.As(err, &reqErr) {
+			result.Fields = append(result.Fields, FieldError{Field: "request", Message: err.Error()})
+			continue
+		}
+
+		switch {
+		case reqErr.Parameter != nil:
+			result.Fields = append(result.Fields, FieldError{
+				Field:   reqErr.Parameter.Name,
+				Message: message(reqErr),
+			})
+		default:
+			result.InBody = true
+			schemaErrs := schemaErrors(reqErr.Err)
+			if len(schemaErrs) == 0 {
+				result.Fields = append(result.Fields, FieldError{Field: "body", Message: message(reqErr)})
+			}
+			for _, schemaErr := range schemaErrs {
+				field := strings.Join(schemaErr.JSONPointer(), ".")
+				if field == "" {
+					field = "body"
+				}
+				result.Fields = append(result.Fields, FieldError{Field: field, Message: schemaErr.Reason})
+			}
+		}
+	}
+
+	sort.Slice(result.Fields, func(i, j int) bool {
+		return result.Fields[i].Field < result.Fields[j].Field
+	})
+	return result
+}
+
+// flatten unfolds nested multi errors, errors wrapping multi errors are kept whole
+func flatten(err error) []error {
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []error{err}
+	}
+
+	var result []error
+	for _, err := range multi {
+		result = append(result, flatten(err)...)
+	}
+	return result
+}
+
+func schemaErrors(err error) []*openapi3.SchemaError {
+	var result []*openapi3.SchemaError
+	for _, err := range flatten(err) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(err, &schemaErr) {
+			result = append(result, schemaErr)
+		}
+	}
+	return result
+}
+
+// message prefers reason of schema violation over full error text
+func message(reqErr *openapi3filter.RequestError) string {
+	if schemaErrs := schemaErrors(reqErr.Err); len(schemaErrs) != 0 {
+		return schemaErrs[0].Reason
+	}
+	if reqErr.Err != nil {
+		return reqErr.Err.Error()
+	}
+	return reqErr.Reason
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// unread fails test when body is read
+type unread struct {
+	t *testing.T
+}
+
+func (u unread) Read(p []byte) (int, error) {
+	u.t.Fatal("multipart body was read by validation")
+	return 0, io.EOF
+}
+
+func TestValidateRequestMultipart(t *testing.T) {
+	v, err := Load("../../openapi.yml", "/api", false)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+
+	r := httptest.NewRequest("POST", "/api/tenders/tender/attachments", unread{t})
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	_, err = v.ValidateRequest(r)
+	if err != nil {
+		t.Fatalf("ValidateRequest: expected multipart body skipped, got %v", err)
+	}
+
+	r = httptest.NewRequest("POST", "/api/tenders/"+strings.Repeat("x", 101)+"/attachments", unread{t})
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	_, err = v.ValidateRequest(r)
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.InBody {
+		t.Fatalf("ValidateRequest: expected parameters of upload still checked, got %v", err)
+	}
+}
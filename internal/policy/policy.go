@@ -0,0 +1,206 @@
+// Package policy decides whether employee may perform action on tender or bid.
+// Employee gets one role in every organization he is responsible for,
+// actions granted to roles are configurable, see Load.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+)
+
+// Roles of employee in organization
+const (
+	RoleViewer   = "viewer"
+	RoleEditor   = "editor"
+	RoleApprover = "approver"
+	RoleAdmin    = "admin"
+)
+
+// Actions on tenders, bids and organization data
+const (
+	TenderCreate  = "tender.create"
+	TenderView    = "tender.view"
+	TenderEdit    = "tender.edit"
+	TenderStatus  = "tender.status"
+	TenderHistory = "tender.history"
+	BidCreate     = "bid.create"
+	BidView       = "bid.view"
+	BidEdit       = "bid.edit"
+	BidHistory    = "bid.history"
+	BidDecide     = "bid.decide"
+	BidReview     = "bid.review"
+	BidEvaluate   = "bid.evaluate"
+	AuditView     = "audit.view"
+)
+
+var (
+	roles   = []string{RoleViewer, RoleEditor, RoleApprover, RoleAdmin}
+	actions = []string{
+		TenderCreate, TenderView, TenderEdit, TenderStatus, TenderHistory,
+		BidCreate, BidView, BidEdit, BidHistory, BidDecide, BidReview, BidEvaluate,
+		AuditView,
+	}
+	// tenderSide are bid actions performed by organization of bid's tender
+	tenderSide = []string{BidDecide, BidReview, BidEvaluate}
+)
+
+var (
+	ErrUnknownRole   = errors.New("unknown role")
+	ErrUnknownAction = errors.New("unknown action")
+)
+
+// Subject is employee with his roles by organization ids
+type Subject struct {
+	UserId string
+	Roles  map[string]string
+}
+
+// Resource describes tender or bid action is performed on
+type Resource struct {
+	// OrgId is organization owning tender or submitting bid
+	OrgId  string
+	Status string
+	// TenderOrgId is organization of bid's tender, it's empty for tenders
+	TenderOrgId string
+}
+
+// Policy maps roles to actions granted to them
+type Policy struct {
+	// DefaultRole is given to employee made responsible without explicit role
+	DefaultRole string              `json:"defaultRole"`
+	Roles       map[string][]string `json:"roles"`
+}
+
+// Default returns policy used without config file, admin is the default role,
+// so responsibles keep full access to their organization
+func Default() *Policy {
+	viewer := []string{TenderView, TenderHistory, BidView, BidHistory, AuditView}
+	return &Policy{
+		DefaultRole: RoleAdmin,
+		Roles: map[string][]string{
+			RoleViewer:   viewer,
+			RoleEditor:   append(slices.Clone(viewer), TenderCreate, TenderEdit, TenderStatus, BidCreate, BidEdit),
+			RoleApprover: append(slices.Clone(viewer), BidDecide, BidReview, BidEvaluate),
+			RoleAdmin:    slices.Clone(actions),
+		},
+	}
+}
+
+// Load reads policy from json file, roles missing in file are granted nothing
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{}
+	err = json.Unmarshal(data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks that policy refers to known roles and actions only
+func (p *Policy) Validate() error {
+	if !slices.Contains(roles, p.DefaultRole) {
+		return fmt.Errorf("%w: default role %q", ErrUnknownRole, p.DefaultRole)
+	}
+	for role, granted := range p.Roles {
+		if !slices.Contains(roles, role) {
+			return fmt.Errorf("%w: %q", ErrUnknownRole, role)
+		}
+		for _, action := range granted {
+			if !slices.Contains(actions, action) {
+				return fmt.Errorf("%w: %q of role %q", ErrUnknownAction, action, role)
+			}
+		}
+	}
+	return nil
+}
+
+// IsRole reports whether role is one of known roles
+func IsRole(role string) bool {
+	return slices.Contains(roles, role)
+}
+
+// Can reports whether subject's role in organization grants action
+func (p *Policy) Can(sub *Subject, orgId, action string) bool {
+	role, ok := sub.Roles[orgId]
+	if !ok {
+		return false
+	}
+	return slices.Contains(p.Roles[role], action)
+}
+
+// Allows decides whether subject may perform action on resource. Published tenders are seen
+// by everyone, published bids by organization of their tender, bid decisions, reviews
+// and evaluations are made by organization of bid's tender, other actions by owner of resource.
+func (p *Policy) Allows(sub *Subject, action string, res *Resource) bool {
+	if slices.Contains(tenderSide, action) {
+		return p.Can(sub, res.TenderOrgId, action)
+	}
+	if p.Can(sub, res.OrgId, action) {
+		return true
+	}
+
+	switch action {
+	case TenderView:
+		return res.Status == "Published"
+	case BidView:
+		return res.Status == "Published" && p.Can(sub, res.TenderOrgId, action)
+	}
+	return false
+}
+
+// Orgs returns ids of subject's organizations where his role grants action
+func (p *Policy) Orgs(sub *Subject, action string) []string {
+	var result []string
+	for orgId := range sub.Roles {
+		if p.Can(sub, orgId, action) {
+			result = append(result, orgId)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// RolesWith returns roles granted action
+func (p *Policy) RolesWith(action string) []string {
+	var result []string
+	for _, role := range roles {
+		if slices.Contains(p.Roles[role], action) {
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
+// Tender describes tender as resource
+func Tender(tnd *models.Tender) *Resource {
+	return &Resource{
+		OrgId:  tnd.OrgId,
+		Status: tnd.Status,
+	}
+}
+
+// Bid describes bid as resource, tenderOrgId is required by actions of tender organization
+// and by visibility of published bid only
+func Bid(bid *models.Bid, tenderOrgId string) *Resource {
+	return &Resource{
+		OrgId:       bid.OrgId,
+		Status:      bid.Status,
+		TenderOrgId: tenderOrgId,
+	}
+}
@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAllows(t *testing.T) {
+	p := Default()
+	sub := &Subject{
+		UserId: "user",
+		Roles:  map[string]string{"buyer": RoleEditor, "supplier": RoleAdmin, "agency": RoleViewer},
+	}
+
+	tests := []struct {
+		name   string
+		action string
+		res    *Resource
+		want   bool
+	}{
+		{"editor edits tender", TenderEdit, &Resource{OrgId: "buyer", Status: "Created"}, true},
+		{"viewer can't edit tender", TenderEdit, &Resource{OrgId: "agency", Status: "Created"}, false},
+		{"stranger sees published tender", TenderView, &Resource{OrgId: "other", Status: "Published"}, true},
+		{"stranger doesn't see created tender", TenderView, &Resource{OrgId: "other", Status: "Created"}, false},
+		{"tender organization sees published bid", BidView, &Resource{OrgId: "other", Status: "Published", TenderOrgId: "agency"}, true},
+		{"tender organization doesn't see created bid", BidView, &Resource{OrgId: "other", Status: "Created", TenderOrgId: "agency"}, false},
+		{"stranger doesn't see published bid", BidView, &Resource{OrgId: "other", Status: "Published", TenderOrgId: "other"}, false},
+		{"editor of tender organization can't decide", BidDecide, &Resource{OrgId: "other", TenderOrgId: "buyer"}, false},
+		{"admin of tender organization decides", BidDecide, &Resource{OrgId: "other", TenderOrgId: "supplier"}, true},
+		{"bid author doesn't decide on own bid", BidDecide, &Resource{OrgId: "supplier", TenderOrgId: "other"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Allows(sub, tt.action, tt.res); got != tt.want {
+				t.Fatalf("Allows(%s): expected %t, got %t", tt.action, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOrgs(t *testing.T) {
+	p := Default()
+	sub := &Subject{Roles: map[string]string{"c": RoleEditor, "a": RoleAdmin, "b": RoleViewer}}
+
+	if got := p.Orgs(sub, TenderCreate); !slices.Equal(got, []string{"a", "c"}) {
+		t.Fatalf("Orgs: expected [a c], got %v", got)
+	}
+	if got := p.RolesWith(BidDecide); !slices.Equal(got, []string{RoleApprover, RoleAdmin}) {
+		t.Fatalf("RolesWith: expected [approver admin], got %v", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "policy.json")
+	err := os.WriteFile(path, []byte(`{"defaultRole": "viewer", "roles": {"viewer": ["tender.view"], "admin": ["tender.view", "tender.edit"]}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if p.DefaultRole != RoleViewer || len(p.RolesWith(TenderEdit)) != 1 || len(p.RolesWith(BidDecide)) != 0 {
+		t.Fatalf("Load: unexpected policy %+v", p)
+	}
+
+	for _, tt := range []struct {
+		data string
+		err  error
+	}{
+		{`{"defaultRole": "owner", "roles": {}}`, ErrUnknownRole},
+		{`{"defaultRole": "admin", "roles": {"owner": []}}`, ErrUnknownRole},
+		{`{"defaultRole": "admin", "roles": {"admin": ["tender.delete"]}}`, ErrUnknownAction},
+	} {
+		err = os.WriteFile(path, []byte(tt.data), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Load(path)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("Load(%s): expected error %q, got %v", tt.data, tt.err, err)
+		}
+	}
+}
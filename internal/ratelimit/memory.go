@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Memory is in-process Limiter, buckets refilled to full are dropped
+// so idle clients don't hold memory
+type Memory struct {
+	mu        sync.Mutex
+	limit     Limit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemory(limit Limit) *Memory {
+	return &Memory{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *Memory) Allow(key string) Decision {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(m.limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+	b.tokens = m.refill(b, now)
+	b.last = now
+
+	decision := Decision{Limit: m.limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = m.duration(1 - b.tokens)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = m.duration(float64(m.limit.Burst) - b.tokens)
+	return decision
+}
+
+// refill returns tokens of bucket at moment now
+func (m *Memory) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*m.limit.Rate
+	return math.Min(tokens, float64(m.limit.Burst))
+}
+
+// duration returns time of refilling given number of tokens
+func (m *Memory) duration(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / m.limit.Rate * float64(time.Second)))
+}
+
+// sweep drops full buckets at most once per time of refilling empty bucket
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.duration(float64(m.limit.Burst)) {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if m.refill(b, now) >= float64(m.limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemory(Limit{Rate: 2, Burst: 3})
+	m.now = func() time.Time { return now }
+
+	for i := 2; i >= 0; i-- {
+		d := m.Allow("client")
+		if !d.Allowed || d.Remaining != i || d.Limit != 3 {
+			t.Fatalf("Allow: expected allowed with %d remaining, got %+v", i, d)
+		}
+	}
+
+	d := m.Allow("client")
+	if d.Allowed || d.RetryAfter != 500*time.Millisecond || d.Reset != 1500*time.Millisecond {
+		t.Fatalf("Allow: expected denied for 500ms, got %+v", d)
+	}
+
+	d = m.Allow("other")
+	if !d.Allowed {
+		t.Fatalf("Allow: expected other client allowed, got %+v", d)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	d = m.Allow("client")
+	if !d.Allowed || d.Remaining != 0 {
+		t.Fatalf("Allow: expected token refilled, got %+v", d)
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemory(Limit{Rate: 1, Burst: 2})
+	m.now = func() time.Time { return now }
+
+	m.Allow("idle")
+	m.Allow("busy")
+	m.Allow("busy")
+
+	now = now.Add(time.Second)
+	m.Allow("busy")
+	if len(m.buckets) != 2 {
+		t.Fatalf("expected buckets kept until refilling empty bucket, got %d", len(m.buckets))
+	}
+
+	now = now.Add(time.Second)
+	m.Allow("busy")
+	if _, ok := m.buckets["idle"]; ok || len(m.buckets) != 1 {
+		t.Fatalf("expected full bucket of idle client dropped, got %d buckets", len(m.buckets))
+	}
+}
@@ -0,0 +1,31 @@
+// Package ratelimit limits request rate of api clients with token buckets
+package ratelimit
+
+import (
+	"time"
+)
+
+// Limit is token bucket refilled with Rate tokens per second and holding at most Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Decision is result of taking token from client's bucket
+type Decision struct {
+	Allowed bool
+	// Limit is bucket capacity
+	Limit int
+	// Remaining is number of whole tokens left after decision
+	Remaining int
+	// RetryAfter is time until next token, zero when tokens remain
+	RetryAfter time.Duration
+	// Reset is time until bucket is full again
+	Reset time.Duration
+}
+
+// Limiter takes tokens from buckets of clients identified by key. Memory keeps buckets
+// of single api instance, shared backend is needed to limit clients across replicas.
+type Limiter interface {
+	Allow(key string) Decision
+}
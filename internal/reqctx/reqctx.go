@@ -0,0 +1,48 @@
+package reqctx
+
+import (
+	"context"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+)
+
+const (
+	ctxKeyUser ctxKey = iota
+	ctxKeyRequestID
+	ctxKeyOrganization
+)
+
+type ctxKey int8
+
+// WithUser returns copy of ctx carrying authenticated employee
+func WithUser(ctx context.Context, user *models.Employee) context.Context {
+	return context.WithValue(ctx, ctxKeyUser, user)
+}
+
+// User returns authenticated employee stored in ctx
+func User(ctx context.Context) (*models.Employee, bool) {
+	user, ok := ctx.Value(ctxKeyUser).(*models.Employee)
+	return user, ok && user != nil
+}
+
+// WithRequestID returns copy of ctx carrying request identifier
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestID, id)
+}
+
+// RequestID returns request identifier stored in ctx or empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyRequestID).(string)
+	return id
+}
+
+// WithOrganization returns copy of ctx carrying organization the user acts on behalf of
+func WithOrganization(ctx context.Context, orgId string) context.Context {
+	return context.WithValue(ctx, ctxKeyOrganization, orgId)
+}
+
+// Organization returns organization chosen for request or empty string when it isn't chosen
+func Organization(ctx context.Context) string {
+	orgId, _ := ctx.Value(ctxKeyOrganization).(string)
+	return orgId
+}
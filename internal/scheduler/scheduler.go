@@ -0,0 +1,53 @@
+// Package scheduler runs periodic background jobs of the api
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is single run of periodic task, returned error is logged and doesn't stop the schedule
+type Job func(ctx context.Context) error
+
+type Scheduler struct {
+	interval time.Duration
+	logger   *logrus.Entry
+}
+
+func New(interval time.Duration, log *logrus.Logger) *Scheduler {
+	logger := log.WithFields(logrus.Fields{
+		"component": "scheduler",
+	})
+
+	return &Scheduler{
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run calls job every interval until ctx is done, blocks caller
+func (s *Scheduler) Run(ctx context.Context, name string, job Job) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Infof("job %s scheduled every %s", name, s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Infof("job %s stopped", name)
+			return
+		case <-ticker.C:
+			err := job(ctx)
+			switch {
+			case err == nil:
+			case ctx.Err() != nil:
+				// job was cut short by stop, it isn't failure
+				s.logger.Infof("job %s interrupted: %s", name, err)
+			default:
+				s.logger.Errorf("job %s failed with error: %s", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRun(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := New(time.Millisecond, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, "test", func(ctx context.Context) error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return errors.New("job failed")
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run: expected return after ctx is done")
+	}
+	if calls != 3 {
+		t.Fatalf("Run: expected job called after failures until ctx is done, got %d calls", calls)
+	}
+}
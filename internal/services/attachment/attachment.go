@@ -0,0 +1,297 @@
+package attachmentservice
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+// Attachments follow visibility of their tender or bid: files of unpublished
+// ones are available to their organization only
+type Attachments struct {
+	ts      store.Tenders
+	bs      store.Bids
+	rs      store.Responsibles
+	as      store.Attachments
+	blobs   store.BlobStore
+	shared  *services.Shared
+	maxSize int64
+	pl      *policy.Policy
+	logger  *logrus.Entry
+}
+
+// New creates service accepting files up to maxSize bytes
+func New(tenderStore store.Tenders, bidStore store.Bids, responsiblesStore store.Responsibles, attachmentsStore store.Attachments, blobStore store.BlobStore, unitOfWork store.UnitOfWork, maxSize int64, pl *policy.Policy, log *logrus.Logger) *Attachments {
+	logger := log.WithFields(logrus.Fields{
+		"service": "attachment",
+	})
+
+	return &Attachments{
+		ts:      tenderStore,
+		bs:      bidStore,
+		rs:      responsiblesStore,
+		as:      attachmentsStore,
+		blobs:   blobStore,
+		shared:  services.NewShared(unitOfWork, logger),
+		maxSize: maxSize,
+		pl:      pl,
+		logger:  logger,
+	}
+}
+
+// Add attaches file to tender or bid, only employees allowed to edit it may attach files
+func (a *Attachments) Add(ctx context.Context, entityType, entityId string, att *models.Attachment, content io.Reader) (*models.Attachment, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := a.shared.Subject(ctx, a.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// check permissions before reading content, so forbidden uploads don't reach blob store
+	owner, err := a.getOwner(ctx, store.Repositories{Tenders: a.ts, Bids: a.bs, Responsibles: a.rs}, entityType, entityId)
+	if err != nil {
+		return nil, err
+	}
+	if !a.pl.Allows(sub, owner.edit, owner.Resource) {
+		return nil, services.ErrNoPermitions
+	}
+
+	// content is put before record is written, blob left by failed transaction is reused by next upload of same file
+	digest, size, err := a.blobs.Put(ctx, &limitedReader{r: content, left: a.maxSize})
+	if err != nil {
+		if errors.Is(err, services.ErrAttachmentTooLarge) {
+			return nil, services.ErrAttachmentTooLarge
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method Put", err)
+		return nil, err
+	}
+
+	att.EntityType = entityType
+	att.EntityId = entityId
+	att.AuthorId = user.Id
+	att.Size = size
+	att.Digest = digest
+
+	var result *models.Attachment
+	err = a.shared.InTx(ctx, func(repos store.Repositories) error {
+		// entity is read again in transaction, it might be changed while content was uploading
+		owner, err := a.getOwner(ctx, repos, entityType, entityId)
+		if err != nil {
+			return err
+		}
+		if !a.pl.Allows(sub, owner.edit, owner.Resource) {
+			return services.ErrNoPermitions
+		}
+
+		result, err = repos.Attachments.Add(ctx, att)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			a.logger.Errorf("unexpected error: %s on method Add", err)
+			return err
+		}
+		return a.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      owner.OrgId,
+			Action:     models.AuditAttach,
+			EntityType: entityType,
+			EntityId:   entityId,
+			OldVersion: owner.version,
+			NewVersion: owner.version,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// List returns attachments of tender or bid visible to the user
+func (a *Attachments) List(ctx context.Context, entityType, entityId string) ([]*models.Attachment, error) {
+	err := a.checkVisible(ctx, entityType, entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.as.GetList(ctx, entityType, entityId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method GetList", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Open returns attachment of tender or bid with its content, caller has to close it
+func (a *Attachments) Open(ctx context.Context, entityType, entityId, attachmentId string) (*models.Attachment, io.ReadCloser, error) {
+	err := a.checkVisible(ctx, entityType, entityId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	att, err := a.as.Get(ctx, attachmentId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, nil, services.ErrNoSuchAttachment
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method Get", err)
+		return nil, nil, err
+	}
+	if att.EntityType != entityType || att.EntityId != entityId {
+		return nil, nil, services.ErrNoSuchAttachment
+	}
+
+	content, err := a.blobs.Open(ctx, att.Digest)
+	if err != nil {
+		// record without content means blob store lost it
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method Open", err)
+		return nil, nil, err
+	}
+	return att, content, nil
+}
+
+// owner describes tender or bid attachments belong to
+type owner struct {
+	*policy.Resource
+	version int64
+	// view and edit are policy actions of owner's kind
+	view string
+	edit string
+}
+
+func (a *Attachments) getOwner(ctx context.Context, repos store.Repositories, entityType, entityId string) (*owner, error) {
+	switch entityType {
+	case models.AttachmentTender:
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, entityId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return nil, services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return nil, services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return nil, interrupted
+			}
+			a.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return nil, err
+		}
+		return &owner{
+			Resource: policy.Tender(tenderCondition),
+			version:  tenderCondition.Version,
+			view:     policy.TenderView,
+			edit:     policy.TenderEdit,
+		}, nil
+	case models.AttachmentBid:
+		bidCondition, err := repos.Bids.GetCondition(ctx, entityId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return nil, services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return nil, services.ErrNoSuchBid
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return nil, interrupted
+			}
+			a.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return nil, err
+		}
+
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, bidCondition.TenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return nil, services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return nil, services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return nil, interrupted
+			}
+			a.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return nil, err
+		}
+		return &owner{
+			Resource: policy.Bid(bidCondition, tenderCondition.OrgId),
+			version:  bidCondition.Version,
+			view:     policy.BidView,
+			edit:     policy.BidEdit,
+		}, nil
+	default:
+		return nil, services.ErrNoSucnResource
+	}
+}
+
+// checkVisible applies visibility rules of tenders and bids, see policy.Policy.Allows
+func (a *Attachments) checkVisible(ctx context.Context, entityType, entityId string) error {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return services.ErrNotAuthenticated
+	}
+
+	sub, err := a.shared.Subject(ctx, a.rs, user.Id)
+	if err != nil {
+		return err
+	}
+
+	owner, err := a.getOwner(ctx, store.Repositories{Tenders: a.ts, Bids: a.bs, Responsibles: a.rs}, entityType, entityId)
+	if err != nil {
+		return err
+	}
+
+	if !a.pl.Allows(sub, owner.view, owner.Resource) {
+		return services.ErrNoPermitions
+	}
+	return nil
+}
+
+// limitedReader fails with ErrAttachmentTooLarge once more than left bytes are read
+type limitedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// one byte over the limit is read to tell content of exactly limit size from larger one
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, services.ErrAttachmentTooLarge
+	}
+	return n, err
+}
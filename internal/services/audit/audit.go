@@ -0,0 +1,74 @@
+package auditservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+type Audit struct {
+	as     store.Audit
+	rs     store.Responsibles
+	pl     *policy.Policy
+	logger *logrus.Entry
+}
+
+func New(auditStore store.Audit, responsiblesStore store.Responsibles, pl *policy.Policy, log *logrus.Logger) *Audit {
+	logger := log.WithFields(logrus.Fields{
+		"service": "audit",
+	})
+
+	return &Audit{
+		as:     auditStore,
+		rs:     responsiblesStore,
+		pl:     pl,
+		logger: logger,
+	}
+}
+
+// List returns audit log of organization, newest entries go first
+func (a *Audit) List(ctx context.Context, orgId string, limit, offset int64) ([]*models.AuditEntry, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	roles, err := a.rs.GetRoles(ctx, user.Id)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoPermitions
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method GetRoles", err)
+		return nil, err
+	}
+
+	sub := &policy.Subject{UserId: user.Id, Roles: roles}
+	if !a.pl.Allows(sub, policy.AuditView, &policy.Resource{OrgId: orgId}) {
+		return nil, services.ErrNoPermitions
+	}
+
+	entries, err := a.as.GetOrgList(ctx, orgId, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method GetOrgList", err)
+		return nil, err
+	}
+	return entries, nil
+}
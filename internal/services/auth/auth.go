@@ -0,0 +1,109 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/config"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type claims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+type Auth struct {
+	rs       store.Responsibles
+	secret   []byte
+	tokenTTL time.Duration
+	logger   *logrus.Entry
+}
+
+func New(responsiblesStore store.Responsibles, cfg config.Auth, log *logrus.Logger) *Auth {
+	logger := log.WithFields(logrus.Fields{
+		"service": "auth",
+	})
+
+	return &Auth{
+		rs:       responsiblesStore,
+		secret:   []byte(cfg.Secret),
+		tokenTTL: cfg.TokenTTL,
+		logger:   logger,
+	}
+}
+
+// IssueToken signs access token for employee with given username
+func (a *Auth) IssueToken(ctx context.Context, username string) (string, time.Time, error) {
+	userId, err := a.rs.GetUserId(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return "", time.Time{}, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrUserNotFound) {
+			return "", time.Time{}, services.ErrNoSuchUser
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return "", time.Time{}, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method GetUserId", err)
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(a.tokenTTL)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}).SignedString(a.secret)
+	if err != nil {
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return "", time.Time{}, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on signing token", err)
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// Authenticate verifies access token and resolves employee it was issued for
+func (a *Auth) Authenticate(ctx context.Context, token string) (*models.Employee, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		return a.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, services.ErrTokenExpired
+		}
+		return nil, services.ErrInvalidToken
+	}
+
+	employee, err := a.rs.GetEmployee(ctx, c.Subject)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrUserNotFound) {
+			return nil, services.ErrInvalidToken
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		a.logger.Errorf("unexpected error: %s on method GetEmployee", err)
+		return nil, err
+	}
+
+	return employee, nil
+}
@@ -1,22 +1,33 @@
 package bidservice
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
 	"github.com/sirupsen/logrus"
 )
 
+// decisionQuorum is the maximum number of approvals required to accept a bid,
+// organizations with fewer responsibles allowed to decide need approvals from all of them
+const decisionQuorum = 3
+
 type Bider struct {
 	ts     store.Tenders
 	bs     store.Bids
 	rs     store.Responsibles
+	es     store.Evaluations
+	shared *services.Shared
+	pl     *policy.Policy
 	logger *logrus.Entry
 }
 
-func New(tenderStore store.Tenders, bidStorage store.Bids, responsiblesStore store.Responsibles, log *logrus.Logger) *Bider {
+func New(tenderStore store.Tenders, bidStorage store.Bids, responsiblesStore store.Responsibles, evaluationsStore store.Evaluations, unitOfWork store.UnitOfWork, pl *policy.Policy, log *logrus.Logger) *Bider {
 	logger := log.WithFields(logrus.Fields{
 		"service": "bider",
 	})
@@ -25,30 +36,44 @@ func New(tenderStore store.Tenders, bidStorage store.Bids, responsiblesStore sto
 		ts:     tenderStore,
 		bs:     bidStorage,
 		rs:     responsiblesStore,
+		es:     evaluationsStore,
+		shared: services.NewShared(unitOfWork, logger),
+		pl:     pl,
 		logger: logger,
 	}
 }
 
-func (b *Bider) Create(bid *models.Bid) (*models.Bid, error) {
+func (b *Bider) Create(ctx context.Context, bid *models.Bid) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authenticated user may create bids only on behalf of himself or one of his organizations,
+	// bid of the user is submitted on behalf of the organization he acts for
 	var orgId string
-	var err error
 	if bid.AuthorType == "Organization" {
 		orgId = bid.AuthorId
-	} else {
-		orgId, err = b.rs.ResponcibleForOrg(bid.AuthorId)
+	}
+	if bid.AuthorType == "User" {
+		if bid.AuthorId != user.Id {
+			return nil, services.ErrNoPermitions
+		}
+		orgId, err = services.ActingOrg(sub.Roles)
 		if err != nil {
-			if errors.Is(err, store.ErrConnClosed) {
-				return nil, services.ErrServiceDatabaseDisconnected
-			}
-			if errors.Is(err, store.ErrRecordNotFound) {
-				return nil, services.ErrNoSuchUser
-			}
-			b.logger.Errorf("unexpected error: %s on method Create", err)
 			return nil, err
 		}
 	}
+	if !b.pl.Allows(sub, policy.BidCreate, &policy.Resource{OrgId: orgId}) {
+		return nil, services.ErrNoPermitions
+	}
 
-	tenderCondition, err := b.ts.GetCondition(bid.TenderId, store.Latest)
+	tenderCondition, err := b.ts.GetCondition(ctx, bid.TenderId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
@@ -56,44 +81,62 @@ func (b *Bider) Create(bid *models.Bid) (*models.Bid, error) {
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return nil, services.ErrNoSuchTender
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
 
-	if tenderCondition.OrgId != "Published" && tenderCondition.OrgId != orgId {
+	if !b.pl.Allows(sub, policy.TenderView, policy.Tender(tenderCondition)) {
 		return nil, services.ErrNoPermitions
 	}
 
-	data, err := b.bs.Create(bid, orgId)
+	if tenderCondition.Expired(time.Now()) {
+		return nil, services.ErrDeadlinePassed
+	}
+
+	var data *models.Bid
+	err = b.shared.InTx(ctx, func(repos store.Repositories) error {
+		data, err = repos.Bids.Create(ctx, bid, orgId)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method Create", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      orgId,
+			Action:     models.AuditCreate,
+			EntityType: models.AuditBid,
+			EntityId:   data.Id,
+			NewVersion: data.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		b.logger.Errorf("unexpected error: %s on method Create", err)
 		return nil, err
 	}
 	return data, nil
 }
 
-func (b *Bider) GetByName(limit, offset int64, username string) ([]*models.Bid, error) {
-	// получить user_id
-	userId, err := b.rs.GetUserId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		b.logger.Errorf("unexpected error: %s on method GetUserId", err)
-		return nil, err
+func (b *Bider) GetByName(ctx context.Context, limit, offset int64) ([]*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	result, err := b.bs.GetUserList(limit, offset, userId)
+	result, err := b.bs.GetUserList(ctx, limit, offset, user.Id)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetLimitedList", err)
 		return nil, err
 	}
@@ -101,23 +144,18 @@ func (b *Bider) GetByName(limit, offset int64, username string) ([]*models.Bid,
 	return result, nil
 }
 
-func (b *Bider) GetTenderBids(limit, offset int64, tenderId, username string) ([]*models.Bid, error) {
-	userOrgId, err := b.rs.GetOrgId(username)
+func (b *Bider) GetTenderBids(ctx context.Context, limit, offset int64, tenderId, order string) ([]*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		b.logger.Errorf("unexpected error: %s on method GetOrgId", err)
 		return nil, err
 	}
 
-	tenderCondition, err := b.ts.GetCondition(tenderId, store.Latest)
+	tenderCondition, err := b.ts.GetCondition(ctx, tenderId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
@@ -125,325 +163,559 @@ func (b *Bider) GetTenderBids(limit, offset int64, tenderId, username string) ([
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return nil, services.ErrNoSuchTender
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
 
-	if tenderCondition.Status != "Published" && tenderCondition.OrgId != userOrgId {
+	if !b.pl.Allows(sub, policy.TenderView, policy.Tender(tenderCondition)) {
 		return nil, services.ErrNoPermitions
 	}
 
-	result, err := b.bs.GetTenderList(limit, offset, tenderId, userOrgId)
+	result, err := b.bs.GetTenderList(ctx, limit, offset, tenderId, b.pl.Orgs(sub, policy.BidView), order)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetTenderList", err)
 		return nil, err
 	}
 	return result, nil
 }
 
-func (b *Bider) GetStat(bidId, username string) (string, error) {
-	userOrgId, err := b.rs.GetOrgId(username)
+func (b *Bider) GetStat(ctx context.Context, bidId string) (string, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return "", services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
+	if err != nil {
+		return "", err
+	}
+
+	bidCondition, err := b.bs.GetCondition(ctx, bidId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return "", services.ErrServiceDatabaseDisconnected
 		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return "", services.ErrNoSuchUser
-		}
 		if errors.Is(err, store.ErrRecordNotFound) {
-			return "", services.ErrNoPermitions
+			return "", services.ErrNoSuchBid
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return "", interrupted
 		}
-		b.logger.Errorf("unexpected error: %s on method GetUserId", err)
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return "", err
 	}
 
-	bidCondition, err := b.bs.GetCondition(bidId, store.Latest)
+	tenderCond, err := b.ts.GetCondition(ctx, bidCondition.TenderId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return "", services.ErrServiceDatabaseDisconnected
 		}
 		if errors.Is(err, store.ErrRecordNotFound) {
-			return "", services.ErrNoSuchBid
+			return "", services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return "", interrupted
 		}
 		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return "", err
 	}
 
-	if bidCondition.Status != "Published" {
-		if bidCondition.AuthorType == "Organization" {
-			if bidCondition.AuthorId != userOrgId {
-				return "", services.ErrNoPermitions
-			}
-			return bidCondition.Status, nil
-		} else {
-			bidOrgId, err := b.rs.ResponcibleForOrg(bidCondition.AuthorId)
-			if err != nil {
-				if errors.Is(err, store.ErrConnClosed) {
-					return "", services.ErrServiceDatabaseDisconnected
-				}
-				if errors.Is(err, store.ErrRecordNotFound) {
-					return "", services.ErrNoSuchBid
-				}
-				b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-				return "", err
-			}
+	if !b.pl.Allows(sub, policy.BidView, policy.Bid(bidCondition, tenderCond.OrgId)) {
+		return "", services.ErrNoPermitions
+	}
 
-			if bidOrgId != userOrgId {
-				return "", services.ErrNoPermitions
-			}
-		}
-	} else {
-		bidOrgId, err := b.rs.ResponcibleForOrg(bidCondition.AuthorId)
+	return bidCondition.Status, nil
+}
+func (b *Bider) ChangeStat(ctx context.Context, bidId, status string, ifMatch int64) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	var result *models.Bid
+	err := b.shared.InTx(ctx, func(repos store.Repositories) error {
+		bidCondition, err := repos.Bids.GetCondition(ctx, bidId, store.Latest)
 		if err != nil {
 			if errors.Is(err, store.ErrConnClosed) {
-				return "", services.ErrServiceDatabaseDisconnected
+				return services.ErrServiceDatabaseDisconnected
 			}
 			if errors.Is(err, store.ErrRecordNotFound) {
-				return "", services.ErrNoSuchBid
+				return services.ErrNoSuchBid
 			}
-			b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-			return "", err
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
 		}
 
-		tenderCond, err := b.ts.GetCondition(bidCondition.TenderId, store.Latest)
+		sub, err := b.shared.Subject(ctx, repos.Responsibles, user.Id)
 		if err != nil {
-			if errors.Is(err, store.ErrConnClosed) {
-				return "", services.ErrServiceDatabaseDisconnected
-			}
-			if errors.Is(err, store.ErrRecordNotFound) {
-				return "", services.ErrNoSuchTender
-			}
-			b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-			return "", err
+			return err
 		}
 
-		if userOrgId != bidOrgId && userOrgId != tenderCond.OrgId {
-			return "", services.ErrNoPermitions
+		if !b.pl.Allows(sub, policy.BidEdit, policy.Bid(bidCondition, "")) {
+			return services.ErrNoPermitions
 		}
-	}
 
-	return bidCondition.Status, nil
-}
-func (b *Bider) ChangeStat(bidId, status, username string) (*models.Bid, error) {
-	bidCondition, err := b.bs.GetCondition(bidId, store.Latest)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchBid
+		if decided(bidCondition) {
+			return services.ErrBidDecided
 		}
-		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
-		return nil, err
-	}
 
-	userOrgId, err := b.rs.GetOrgId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if ifMatch != services.AnyVersion && bidCondition.Version != ifMatch {
+			return &services.VersionError{Err: services.ErrVersionMismatch, Current: bidCondition.Version}
 		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
+
+		if bidCondition.Status == status {
+			result = bidCondition
+			return nil
 		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
+
+		bidCondition.Status = status
+		bidCondition.Version += 1
+
+		result, err = repos.Bids.UpdateCondition(ctx, bidCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      bidCondition.OrgId,
+			Action:     models.AuditChangeStatus,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, b.shared.VersionConflict(ctx, models.AuditBid, bidId)
 		}
-		b.logger.Errorf("unexpected error: %s on method GetOrgId", err)
 		return nil, err
 	}
+	return result, nil
+}
 
-	if bidCondition.AuthorType == "Organization" {
-		if bidCondition.AuthorId != userOrgId {
-			return nil, services.ErrNoPermitions
-		}
-	} else {
-		bidOrgId, err := b.rs.ResponcibleForOrg(bidCondition.AuthorId)
+func (b *Bider) Edit(ctx context.Context, bid *models.Bid, bidId string, ifMatch int64) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	var result *models.Bid
+	err := b.shared.InTx(ctx, func(repos store.Repositories) error {
+		bidCondition, err := repos.Bids.GetCondition(ctx, bidId, store.Latest)
 		if err != nil {
 			if errors.Is(err, store.ErrConnClosed) {
-				return nil, services.ErrServiceDatabaseDisconnected
+				return services.ErrServiceDatabaseDisconnected
 			}
 			if errors.Is(err, store.ErrRecordNotFound) {
-				return nil, services.ErrNoPermitions
+				return services.ErrNoSuchBid
 			}
-			b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-			return nil, err
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
 		}
 
-		if bidOrgId != userOrgId {
-			return nil, services.ErrNoPermitions
+		sub, err := b.shared.Subject(ctx, repos.Responsibles, user.Id)
+		if err != nil {
+			return err
 		}
-	}
 
-	if bidCondition.Status == status {
-		return bidCondition, nil
-	}
+		if !b.pl.Allows(sub, policy.BidEdit, policy.Bid(bidCondition, "")) {
+			return services.ErrNoPermitions
+		}
 
-	bidCondition.Status = status
-	bidCondition.Version += 1
+		if decided(bidCondition) {
+			return services.ErrBidDecided
+		}
 
-	result, err := b.bs.UpdateCondition(bidCondition)
+		if ifMatch != services.AnyVersion && bidCondition.Version != ifMatch {
+			return &services.VersionError{Err: services.ErrVersionMismatch, Current: bidCondition.Version}
+		}
+
+		var count int
+		if bid.Name != "" && bidCondition.Name != bid.Name {
+			bidCondition.Name = bid.Name
+			count++
+		}
+		if bid.Description != "" && bidCondition.Description != bid.Description {
+			bidCondition.Description = bid.Description
+			count++
+		}
+		if bid.Amount != nil && (bidCondition.Amount == nil || *bidCondition.Amount != *bid.Amount || bidCondition.Currency != bid.Currency) {
+			bidCondition.Amount = bid.Amount
+			bidCondition.Currency = bid.Currency
+			count++
+		}
+		if bid.DeliveryDays != nil && (bidCondition.DeliveryDays == nil || *bidCondition.DeliveryDays != *bid.DeliveryDays) {
+			bidCondition.DeliveryDays = bid.DeliveryDays
+			count++
+		}
+		if count == 0 {
+			result = bidCondition
+			return nil
+		}
+
+		bidCondition.Version += 1
+
+		result, err = repos.Bids.UpdateCondition(ctx, bidCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      bidCondition.OrgId,
+			Action:     models.AuditEdit,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, b.shared.VersionConflict(ctx, models.AuditBid, bidId)
 		}
-		b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
 	return result, nil
 }
+func (b *Bider) Sumbit(ctx context.Context, bidId, decision string) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
 
-func (b *Bider) Edit(bid *models.Bid, bidId, username string) (*models.Bid, error) {
-	bidCondition, err := b.bs.GetCondition(bidId, store.Latest)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+	var result *models.Bid
+	var tenderId string
+	tenderConflict := false
+	err := b.shared.InTx(ctx, func(repos store.Repositories) error {
+		sub, err := b.shared.Subject(ctx, repos.Responsibles, user.Id)
+		if err != nil {
+			return err
 		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchBid
+
+		// concurrent decisions on the bid wait for each other, otherwise each of them
+		// could count approvals without the others and quorum would never be reached
+		err = repos.Bids.Lock(ctx, bidId)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchBid
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method Lock", err)
+			return err
 		}
-		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
-		return nil, err
-	}
 
-	userOrgId, err := b.rs.GetOrgId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		bidCondition, err := repos.Bids.GetCondition(ctx, bidId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchBid
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
 		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
+
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, bidCondition.TenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
 		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
+		tenderId = tenderCondition.Id
+
+		if !b.pl.Allows(sub, policy.BidDecide, policy.Bid(bidCondition, tenderCondition.OrgId)) {
+			return services.ErrNoPermitions
 		}
-		b.logger.Errorf("unexpected error: %s on method GetOrgId", err)
-		return nil, err
-	}
 
-	if bidCondition.AuthorType == "Organization" {
-		if bidCondition.AuthorId != userOrgId {
-			return nil, services.ErrNoPermitions
+		if bidCondition.Status != "Published" || tenderCondition.Status == "Closed" {
+			return services.ErrDecisionNotAllowed
 		}
-	} else {
-		bidOrgId, err := b.rs.ResponcibleForOrg(bidCondition.AuthorId)
+
+		err = repos.Bids.AddDecision(ctx, bidId, user.Id, decision)
 		if err != nil {
 			if errors.Is(err, store.ErrConnClosed) {
-				return nil, services.ErrServiceDatabaseDisconnected
+				return services.ErrServiceDatabaseDisconnected
 			}
-			if errors.Is(err, store.ErrRecordNotFound) {
-				return nil, services.ErrNoPermitions
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
 			}
-			b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-			return nil, err
+			b.logger.Errorf("unexpected error: %s on method AddDecision", err)
+			return err
+		}
+
+		err = b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditDecision,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: bidCondition.Version,
+			NewVersion: bidCondition.Version,
+		})
+		if err != nil {
+			return err
 		}
 
-		if bidOrgId != userOrgId {
-			return nil, services.ErrNoPermitions
+		// Any rejection is final for the bid
+		if decision == "Rejected" {
+			result, err = b.setDecisionStatus(ctx, repos.Bids, bidCondition, "Rejected")
+			if err != nil {
+				return err
+			}
+			return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+				OrgId:      tenderCondition.OrgId,
+				Action:     models.AuditChangeStatus,
+				EntityType: models.AuditBid,
+				EntityId:   bidId,
+				OldVersion: result.Version - 1,
+				NewVersion: result.Version,
+			})
 		}
-	}
 
-	var count int
-	if bid.Name != "" && bidCondition.Name != bid.Name {
-		bidCondition.Name = bid.Name
-		count++
-	}
-	if bid.Description != "" && bidCondition.Description != bid.Description {
-		bidCondition.Description = bid.Description
-		count++
-	}
-	if count == 0 {
-		return bidCondition, nil
-	}
+		approved, _, err := repos.Bids.GetDecisionsCount(ctx, bidId)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetDecisionsCount", err)
+			return err
+		}
 
-	bidCondition.Version += 1
+		// only responsibles allowed to decide make up the quorum
+		responsibles, err := repos.Responsibles.CountResponsibles(ctx, tenderCondition.OrgId, b.pl.RolesWith(policy.BidDecide))
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method CountResponsibles", err)
+			return err
+		}
 
-	result, err := b.bs.UpdateCondition(bidCondition)
+		if approved < min(decisionQuorum, responsibles) {
+			result = bidCondition
+			return nil
+		}
+
+		result, err = b.setDecisionStatus(ctx, repos.Bids, bidCondition, "Approved")
+		if err != nil {
+			return err
+		}
+
+		err = b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditChangeStatus,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+		if err != nil {
+			return err
+		}
+
+		tenderCondition.Status = "Closed"
+		tenderCondition.Version += 1
+
+		_, err = repos.Tenders.UpdateCondition(ctx, tenderCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			// tender isn't locked, it was edited since it was read
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				tenderConflict = true
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditChangeStatus,
+			EntityType: models.AuditTender,
+			EntityId:   tenderCondition.Id,
+			OldVersion: tenderCondition.Version - 1,
+			NewVersion: tenderCondition.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if errors.Is(err, services.ErrVersionConflict) && tenderConflict {
+			return nil, b.shared.VersionConflict(ctx, models.AuditTender, tenderId)
+		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, b.shared.VersionConflict(ctx, models.AuditBid, bidId)
 		}
-		b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
 	return result, nil
 }
-func (b *Bider) Sumbit(bidId, decision, username string) (*models.Bid, error) {
-	panic("unimplemented")
+
+// decided reports whether bid is approved or rejected, decision on bid is final
+func decided(bid *models.Bid) bool {
+	return bid.Status == "Approved" || bid.Status == "Rejected"
 }
 
-func (b *Bider) Rollback(bidId string, version int64, username string) (*models.Bid, error) {
-	bidCondition, err := b.bs.GetCondition(bidId, version)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchBid
-		}
-		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
-		return nil, err
-	}
+// setDecisionStatus writes new bid version with final decision status
+func (b *Bider) setDecisionStatus(ctx context.Context, bs store.Bids, bidCondition *models.Bid, status string) (*models.Bid, error) {
+	bidCondition.Status = status
+	bidCondition.Version += 1
 
-	userOrgId, err := b.rs.GetOrgId(username)
+	result, err := bs.UpdateCondition(ctx, bidCondition)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
+		if errors.Is(err, store.ErrRecordAlreadyExists) {
+			return nil, services.ErrVersionConflict
 		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
 		}
-		b.logger.Errorf("unexpected error: %s on method GetOrgId", err)
+		b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
+	return result, nil
+}
 
-	if bidCondition.AuthorType == "Organization" {
-		if bidCondition.AuthorId != userOrgId {
-			return nil, services.ErrNoPermitions
-		}
-	} else {
-		bidOrgId, err := b.rs.ResponcibleForOrg(bidCondition.AuthorId)
+func (b *Bider) Rollback(ctx context.Context, bidId string, version int64) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	var result *models.Bid
+	err := b.shared.InTx(ctx, func(repos store.Repositories) error {
+		bidCondition, err := repos.Bids.GetCondition(ctx, bidId, version)
 		if err != nil {
 			if errors.Is(err, store.ErrConnClosed) {
-				return nil, services.ErrServiceDatabaseDisconnected
+				return services.ErrServiceDatabaseDisconnected
 			}
 			if errors.Is(err, store.ErrRecordNotFound) {
-				return nil, services.ErrNoPermitions
+				return services.ErrNoSuchBid
 			}
-			b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
-			return nil, err
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
 		}
 
-		if bidOrgId != userOrgId {
-			return nil, services.ErrNoPermitions
+		sub, err := b.shared.Subject(ctx, repos.Responsibles, user.Id)
+		if err != nil {
+			return err
 		}
-	}
 
-	latestVersion, err := b.bs.GetBidLatestVersion(bidId)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if !b.pl.Allows(sub, policy.BidEdit, policy.Bid(bidCondition, "")) {
+			return services.ErrNoPermitions
 		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchBid
+
+		latest, err := repos.Bids.GetCondition(ctx, bidId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchBid
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		if decided(latest) {
+			return services.ErrBidDecided
 		}
-		b.logger.Errorf("unexpected error: %s on method GetBidLatestVersion", err)
-		return nil, err
-	}
 
-	bidCondition.Version = latestVersion + 1
+		bidCondition.Version = latest.Version + 1
 
-	result, err := b.bs.UpdateCondition(bidCondition)
+		result, err = repos.Bids.UpdateCondition(ctx, bidCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      bidCondition.OrgId,
+			Action:     models.AuditRollback,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, b.shared.VersionConflict(ctx, models.AuditBid, bidId)
 		}
-		b.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
 	return result, nil
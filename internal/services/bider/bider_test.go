@@ -0,0 +1,96 @@
+package bidservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+	"github.com/sirupsen/logrus"
+)
+
+// racingWork runs units of work where every tender update is preceded by concurrent one
+type racingWork struct {
+	store.UnitOfWork
+}
+
+func (u racingWork) Do(ctx context.Context, fn func(repos store.Repositories) error) error {
+	return u.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+		repos.Tenders = racingTenders{repos.Tenders}
+		return fn(repos)
+	})
+}
+
+type racingTenders struct {
+	store.Tenders
+}
+
+func (r racingTenders) UpdateCondition(ctx context.Context, newCondition *models.Tender) (*models.Tender, error) {
+	concurrent := *newCondition
+	_, err := r.Tenders.UpdateCondition(ctx, &concurrent)
+	if err != nil {
+		return nil, err
+	}
+	return r.Tenders.UpdateCondition(ctx, newCondition)
+}
+
+func TestSubmitTenderConflict(t *testing.T) {
+	ctx := context.Background()
+	s, f := storetest.NewMemory(t)
+
+	// responsible alone makes up the quorum
+	err := s.Organizations.Revoke(ctx, f.OrgId, f.Colleague.Id)
+	if err != nil {
+		t.Fatalf("Revoke: %s", err)
+	}
+
+	tnd, err := s.Tenders.Create(ctx, &models.Tender{Name: "Roads", Description: "Roads repair", ServType: "Construction"}, &models.Responsible{OrgId: f.OrgId, Username: f.Responsible.Username})
+	if err != nil {
+		t.Fatalf("Create tender: %s", err)
+	}
+	// tender is published and edited so its version differs from bid's one
+	tnd.OrgId = f.OrgId
+	for _, edit := range []func(){
+		func() { tnd.Status = "Published" },
+		func() { tnd.Name = "Roads and bridges" },
+	} {
+		edit()
+		tnd.Version += 1
+		tnd, err = s.Tenders.UpdateCondition(ctx, tnd)
+		if err != nil {
+			t.Fatalf("UpdateCondition tender: %s", err)
+		}
+	}
+
+	bid, err := s.Bids.Create(ctx, &models.Bid{Name: "Asphalt", Description: "Asphalt works", TenderId: tnd.Id, AuthorType: "Organization", AuthorId: f.OtherOrgId}, f.OtherOrgId)
+	if err != nil {
+		t.Fatalf("Create bid: %s", err)
+	}
+	bid.Status = "Published"
+	bid.Version = 2
+	_, err = s.Bids.UpdateCondition(ctx, bid)
+	if err != nil {
+		t.Fatalf("UpdateCondition bid: %s", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	svc := New(s.Tenders, s.Bids, s.Responsibles, s.Evaluations, racingWork{s.UnitOfWork}, policy.Default(), logger)
+
+	_, err = svc.Sumbit(reqctx.WithUser(ctx, &f.Responsible), bid.Id, "Approved")
+	var versionErr *services.VersionError
+	if !errors.Is(err, services.ErrVersionConflict) || !errors.As(err, &versionErr) || versionErr.Current != tnd.Version {
+		t.Fatalf("Sumbit: expected conflict on tender of version %d, got %v", tnd.Version, err)
+	}
+
+	got, err := s.Bids.GetCondition(ctx, bid.Id, store.Latest)
+	if err != nil || got.Status != "Published" {
+		t.Fatalf("GetCondition: expected approval discarded, got %+v, %v", got, err)
+	}
+}
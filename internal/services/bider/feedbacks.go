@@ -1,39 +1,28 @@
 package bidservice
 
 import (
+	"context"
 	"errors"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
 )
 
-func (b *Bider) AddFeedback(bidId, bidFeedback, username string) (*models.Bid, error) {
-	userId, err := b.rs.GetUserId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		b.logger.Errorf("unexpected error: %s on method GetUserId", err)
-		return nil, err
+func (b *Bider) AddFeedback(ctx context.Context, bidId, bidFeedback string) (*models.Bid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	userOrgId, err := b.rs.ResponcibleForOrg(userId)
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
 		return nil, err
 	}
 
-	tenderOrgId, err := b.ts.GetOrgIdByBidId(bidId)
+	tenderOrgId, err := b.ts.GetOrgIdByBidId(ctx, bidId)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
@@ -44,15 +33,18 @@ func (b *Bider) AddFeedback(bidId, bidFeedback, username string) (*models.Bid, e
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return nil, services.ErrNoSucnResource
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetOrgIdByBidId", err)
 		return nil, err
 	}
 
-	if tenderOrgId != userOrgId {
+	if !b.pl.Allows(sub, policy.BidReview, &policy.Resource{TenderOrgId: tenderOrgId}) {
 		return nil, services.ErrNoPermitions
 	}
 
-	bidCondition, err := b.bs.GetCondition(bidId, store.Latest)
+	bidCondition, err := b.bs.GetCondition(ctx, bidId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
@@ -60,53 +52,57 @@ func (b *Bider) AddFeedback(bidId, bidFeedback, username string) (*models.Bid, e
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return nil, services.ErrNoSuchBid
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
 
-
 	if bidCondition.Status != "Published" {
 		return nil, services.ErrNoPermitions
 	}
 
-	err = b.bs.AddFeedback(bidId, userId, bidFeedback)
+	err = b.shared.InTx(ctx, func(repos store.Repositories) error {
+		err := repos.Bids.AddFeedback(ctx, bidId, user.Id, bidFeedback)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method AddFeedback", err)
+			return err
+		}
+		return b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderOrgId,
+			Action:     models.AuditFeedback,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: bidCondition.Version,
+			NewVersion: bidCondition.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		b.logger.Errorf("unexpected error: %s on method AddFeedback", err)
 		return nil, err
 	}
 
 	return bidCondition, nil
 }
 
-func (b *Bider) GetReviews(tenderId, authorUsername, requesterUsername string, limit, offset int64) ([]*models.Feedback, error) {
-	reqUserId, err := b.rs.GetUserId(requesterUsername)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		b.logger.Errorf("unexpected error: %s on method GetUserId", err)
-		return nil, err
+func (b *Bider) GetReviews(ctx context.Context, tenderId, authorUsername string, limit, offset int64) ([]*models.Feedback, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	reqUserOrgId, err := b.rs.ResponcibleForOrg(reqUserId)
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		b.logger.Errorf("unexpected error: %s on method ResponcibleForOrg", err)
 		return nil, err
 	}
 
-	tenderCondition, err := b.ts.GetCondition(tenderId, store.Latest)
+	tenderCondition, err := b.ts.GetCondition(ctx, tenderId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
@@ -114,19 +110,25 @@ func (b *Bider) GetReviews(tenderId, authorUsername, requesterUsername string, l
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return nil, services.ErrNoSuchTender
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
 
-	if tenderCondition.OrgId != reqUserOrgId {
+	if !b.pl.Allows(sub, policy.BidReview, &policy.Resource{TenderOrgId: tenderCondition.OrgId}) {
 		return nil, services.ErrNoPermitions
 	}
 
-	result, err := b.bs.GetFeedbacks(tenderId, authorUsername, limit, offset)
+	result, err := b.bs.GetFeedbacks(ctx, tenderId, authorUsername, limit, offset)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		b.logger.Errorf("unexpected error: %s on method GetFeedbacks", err)
 		return nil, err
 	}
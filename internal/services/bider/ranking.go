@@ -0,0 +1,110 @@
+package bidservice
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// GetRanking ranks published bids for tender owner. By price only bids with price are ranked
+// and the lowest one wins, amounts in different currencies aren't comparable, so every
+// currency is ranked separately. By score all published bids are ranked by total score.
+func (b *Bider) GetRanking(ctx context.Context, tenderId, by string) ([]*models.RankedBid, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tenderCondition, err := b.ts.GetCondition(ctx, tenderId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	// ranking is for tender owner: employees managing the tender and ones evaluating its bids
+	owner := b.pl.Allows(sub, policy.TenderEdit, policy.Tender(tenderCondition))
+	evaluator := b.pl.Allows(sub, policy.BidEvaluate, &policy.Resource{TenderOrgId: tenderCondition.OrgId})
+	if !owner && !evaluator {
+		return nil, services.ErrNoPermitions
+	}
+
+	if by == services.RankByScore {
+		return b.rankByScore(ctx, tenderId)
+	}
+
+	bids, err := b.bs.GetRanking(ctx, tenderId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetRanking", err)
+		return nil, err
+	}
+
+	result := make([]*models.RankedBid, 0, len(bids))
+	var rank int64
+	for i, bid := range bids {
+		if i == 0 || bids[i-1].Currency != bid.Currency {
+			rank = 0
+		}
+		rank++
+		result = append(result, &models.RankedBid{Rank: rank, Bid: bid})
+	}
+	return result, nil
+}
+
+func (b *Bider) rankByScore(ctx context.Context, tenderId string) ([]*models.RankedBid, error) {
+	bids, err := b.bs.GetPublishedList(ctx, tenderId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetPublishedList", err)
+		return nil, err
+	}
+
+	criteria, scores, err := b.getEvaluation(ctx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.RankedBid, 0, len(bids))
+	for _, bid := range bids {
+		total := models.ScoreBid(bid.Id, criteria, scores).Total
+		result = append(result, &models.RankedBid{Score: &total, Bid: bid})
+	}
+	// bids are listed in creation order, so earlier bid wins on equal score
+	sort.SliceStable(result, func(i, j int) bool {
+		return *result[i].Score > *result[j].Score
+	})
+	for i, ranked := range result {
+		ranked.Rank = int64(i + 1)
+	}
+	return result, nil
+}
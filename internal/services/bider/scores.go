@@ -0,0 +1,206 @@
+package bidservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// Score saves scores of authenticated evaluator for published bid, every responsible
+// of tender organization evaluates bid on his own and may change his scores later
+func (b *Bider) Score(ctx context.Context, bidId string, scores []*models.Score) (*models.BidScore, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	var result *models.BidScore
+	err := b.shared.InTx(ctx, func(repos store.Repositories) error {
+		sub, err := b.shared.Subject(ctx, repos.Responsibles, user.Id)
+		if err != nil {
+			return err
+		}
+
+		bidCondition, tenderCondition, err := b.getBidWithTender(ctx, repos, bidId)
+		if err != nil {
+			return err
+		}
+
+		if !b.pl.Allows(sub, policy.BidEvaluate, policy.Bid(bidCondition, tenderCondition.OrgId)) {
+			return services.ErrNoPermitions
+		}
+
+		if bidCondition.Status != "Published" || tenderCondition.Status == "Closed" {
+			return services.ErrEvaluationNotAllowed
+		}
+
+		criteria, err := repos.Evaluations.GetCriteria(ctx, tenderCondition.Id)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetCriteria", err)
+			return err
+		}
+		if len(criteria) == 0 {
+			return services.ErrNoCriteria
+		}
+
+		for _, score := range scores {
+			if !hasCriterion(criteria, score.CriterionId) {
+				return services.ErrNoSuchCriterion
+			}
+			score.BidId = bidId
+			score.EvaluatorId = user.Id
+
+			err = repos.Evaluations.SetScore(ctx, score)
+			if err != nil {
+				if errors.Is(err, store.ErrConnClosed) {
+					return services.ErrServiceDatabaseDisconnected
+				}
+				if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+					return interrupted
+				}
+				b.logger.Errorf("unexpected error: %s on method SetScore", err)
+				return err
+			}
+		}
+
+		err = b.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditScore,
+			EntityType: models.AuditBid,
+			EntityId:   bidId,
+			OldVersion: bidCondition.Version,
+			NewVersion: bidCondition.Version,
+		})
+		if err != nil {
+			return err
+		}
+
+		tenderScores, err := repos.Evaluations.GetScores(ctx, tenderCondition.Id)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			b.logger.Errorf("unexpected error: %s on method GetScores", err)
+			return err
+		}
+		result = models.ScoreBid(bidId, criteria, tenderScores)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetScore returns total score of bid, available to responsibles of tender organization
+func (b *Bider) GetScore(ctx context.Context, bidId string) (*models.BidScore, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	bidCondition, tenderCondition, err := b.getBidWithTender(ctx, store.Repositories{Tenders: b.ts, Bids: b.bs}, bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b.pl.Allows(sub, policy.BidEvaluate, policy.Bid(bidCondition, tenderCondition.OrgId)) {
+		return nil, services.ErrNoPermitions
+	}
+
+	criteria, scores, err := b.getEvaluation(ctx, tenderCondition.Id)
+	if err != nil {
+		return nil, err
+	}
+	return models.ScoreBid(bidId, criteria, scores), nil
+}
+
+// getBidWithTender returns latest conditions of bid and its tender
+func (b *Bider) getBidWithTender(ctx context.Context, repos store.Repositories, bidId string) (*models.Bid, *models.Tender, error) {
+	bidCondition, err := repos.Bids.GetCondition(ctx, bidId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, nil, services.ErrNoSuchBid
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, nil, err
+	}
+
+	tenderCondition, err := repos.Tenders.GetCondition(ctx, bidCondition.TenderId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, nil, services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, nil, err
+	}
+	return bidCondition, tenderCondition, nil
+}
+
+// getEvaluation returns criteria of tender and scores of all its bids
+func (b *Bider) getEvaluation(ctx context.Context, tenderId string) ([]*models.Criterion, []*models.Score, error) {
+	criteria, err := b.es.GetCriteria(ctx, tenderId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCriteria", err)
+		return nil, nil, err
+	}
+
+	scores, err := b.es.GetScores(ctx, tenderId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetScores", err)
+		return nil, nil, err
+	}
+	return criteria, scores, nil
+}
+
+func hasCriterion(criteria []*models.Criterion, criterionId string) bool {
+	for _, c := range criteria {
+		if c.Id == criterionId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package bidservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func (b *Bider) GetVersions(ctx context.Context, bidId string, limit, offset int64) ([]*models.Bid, error) {
+	err := b.checkHistoryAccess(ctx, bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := b.bs.GetVersionsList(ctx, bidId, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetVersionsList", err)
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (b *Bider) Diff(ctx context.Context, bidId string, from, to int64) (*models.VersionsDiff, error) {
+	err := b.checkHistoryAccess(ctx, bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCondition, err := b.bs.GetCondition(ctx, bidId, from)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchBid
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	toCondition, err := b.bs.GetCondition(ctx, bidId, to)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchBid
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	return models.DiffBids(fromCondition, toCondition), nil
+}
+
+// checkHistoryAccess allows version history only to responsibles of organization the bid is submitted on behalf of
+func (b *Bider) checkHistoryAccess(ctx context.Context, bidId string) error {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return services.ErrNotAuthenticated
+	}
+
+	sub, err := b.shared.Subject(ctx, b.rs, user.Id)
+	if err != nil {
+		return err
+	}
+
+	bidCondition, err := b.bs.GetCondition(ctx, bidId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return services.ErrNoSuchBid
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		b.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return err
+	}
+
+	if !b.pl.Allows(sub, policy.BidHistory, policy.Bid(bidCondition, "")) {
+		return services.ErrNoPermitions
+	}
+	return nil
+}
@@ -1,15 +1,71 @@
 package services
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
 
 var (
-	ErrNoSuchUser                  = errors.New("user doesn't exists")
+	ErrNoSuchUser                  = errors.New("user doesn't exist")
 	ErrUserExists                  = errors.New("user already exists")
 	ErrConnectionLost              = errors.New("db connection lost")
 	ErrServiceDatabaseDisconnected = errors.New("service database not available")
+	ErrServiceTimeout              = errors.New("service database timed out")
+	ErrRequestCanceled             = errors.New("request canceled")
 	ErrNothingToChange             = errors.New("nothing to change")
-	ErrNoPermitions                = errors.New("user donn't have permitions")
-	ErrNoSuchTender                = errors.New("tender doesn't exists")
+	ErrNoPermitions                = errors.New("user doesn't have permissions")
+	ErrNoSuchTender                = errors.New("tender doesn't exist")
 	ErrNoSucnResource              = errors.New("no resource with such identifier")
-	ErrNoSuchBid                   = errors.New("bid doesn't exists")
+	ErrNoSuchBid                   = errors.New("bid doesn't exist")
+	ErrDecisionNotAllowed          = errors.New("decision can't be submitted for this bid")
+	ErrBidDecided                  = errors.New("bid is already approved or rejected")
+	ErrTenderClosed                = errors.New("tender is closed")
+	ErrNotAuthenticated            = errors.New("user is not authenticated")
+	ErrInvalidToken                = errors.New("invalid access token")
+	ErrTokenExpired                = errors.New("access token expired")
+	ErrVersionMismatch             = errors.New("record version doesn't match expected one")
+	ErrVersionConflict             = errors.New("record was concurrently modified")
+	ErrDeadlinePassed              = errors.New("tender deadline has passed")
+	ErrEvaluationNotAllowed        = errors.New("evaluation can't be changed for this tender or bid")
+	ErrNoCriteria                  = errors.New("tender has no evaluation criteria")
+	ErrNoSuchCriterion             = errors.New("criterion doesn't belong to tender")
+	ErrNoSuchAttachment            = errors.New("attachment doesn't exist")
+	ErrAttachmentTooLarge          = errors.New("attachment exceeds maximum size")
+	ErrNoSuchOrganization          = errors.New("organization doesn't exist")
+	ErrNoSuchEmployee              = errors.New("employee doesn't exist")
+	ErrNotResponsible              = errors.New("employee isn't responsible for organization")
+	ErrResourceInUse               = errors.New("resource has tenders, bids or audit entries")
+	ErrOrganizationRequired        = errors.New("user is responsible for several organizations, organization must be chosen")
+	ErrUnknownRole                 = errors.New("role isn't defined by access policy")
 )
+
+// VersionError carries current version of record for ErrVersionMismatch and ErrVersionConflict
+type VersionError struct {
+	Err     error
+	Current int64
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s, current version is %d", e.Err, e.Current)
+}
+
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
+
+// Interrupted maps error of operation cut short by store timeout or by cancelled ctx,
+// nil is returned for other errors. Interrupted operations aren't failures of the service,
+// so they aren't logged as unexpected. Cancellation is told by ctx as driver may report
+// query cancelled with ctx as timeout.
+func Interrupted(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled) {
+		return ErrRequestCanceled
+	}
+	if errors.Is(err, store.ErrTimeout) {
+		return ErrServiceTimeout
+	}
+	return nil
+}
@@ -0,0 +1,433 @@
+package organizationservice
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+// Organizations manages organizations, employees and responsibility of employees
+// for organizations, every method is available to admins only
+type Organizations struct {
+	os     store.Organizations
+	es     store.Employees
+	admins []string
+	pl     *policy.Policy
+	logger *logrus.Entry
+}
+
+// New creates service administered by employees with given usernames,
+// roles granted to responsibles are checked against given policy
+func New(organizationsStore store.Organizations, employeesStore store.Employees, admins []string, pl *policy.Policy, log *logrus.Logger) *Organizations {
+	logger := log.WithFields(logrus.Fields{
+		"service": "organization",
+	})
+
+	return &Organizations{
+		os:     organizationsStore,
+		es:     employeesStore,
+		admins: admins,
+		pl:     pl,
+		logger: logger,
+	}
+}
+
+func (o *Organizations) CreateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.os.Create(ctx, org)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Create", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *Organizations) GetOrganization(ctx context.Context, orgId string) (*models.Organization, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.getOrganization(ctx, orgId)
+}
+
+func (o *Organizations) ListOrganizations(ctx context.Context, limit, offset int64) ([]*models.Organization, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.os.GetList(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method GetList", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// EditOrganization changes non empty fields of organization
+func (o *Organizations) EditOrganization(ctx context.Context, org *models.Organization, orgId string) (*models.Organization, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := o.getOrganization(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if org.Name != "" && current.Name != org.Name {
+		current.Name = org.Name
+		count++
+	}
+	if org.Description != "" && current.Description != org.Description {
+		current.Description = org.Description
+		count++
+	}
+	if org.Type != "" && current.Type != org.Type {
+		current.Type = org.Type
+		count++
+	}
+	if count == 0 {
+		return current, nil
+	}
+
+	result, err := o.os.Update(ctx, current)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchOrganization
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Update", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteOrganization refuses to delete organization having tenders, bids or audit entries
+func (o *Organizations) DeleteOrganization(ctx context.Context, orgId string) error {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = o.os.Delete(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return services.ErrNoSuchOrganization
+		}
+		if errors.Is(err, store.ErrRecordInUse) {
+			return services.ErrResourceInUse
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Delete", err)
+		return err
+	}
+	return nil
+}
+
+func (o *Organizations) ListResponsibles(ctx context.Context, orgId string) ([]*models.Member, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = o.getOrganization(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.os.GetResponsibles(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method GetResponsibles", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Grant makes employee responsible for organization with given role, empty role means
+// policy default one, granting to responsible employee changes his role
+func (o *Organizations) Grant(ctx context.Context, orgId, userId, role string) error {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if role == "" {
+		role = o.pl.DefaultRole
+	}
+	if !policy.IsRole(role) {
+		return services.ErrUnknownRole
+	}
+
+	_, err = o.getOrganization(ctx, orgId)
+	if err != nil {
+		return err
+	}
+	_, err = o.getEmployee(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	err = o.os.Grant(ctx, orgId, userId, role)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, store.ErrConnClosed) {
+		return services.ErrServiceDatabaseDisconnected
+	}
+	if !errors.Is(err, store.ErrRecordAlreadyExists) {
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Grant", err)
+		return err
+	}
+
+	err = o.os.SetRole(ctx, orgId, userId, role)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return services.ErrNotResponsible
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method SetRole", err)
+		return err
+	}
+	return nil
+}
+
+func (o *Organizations) Revoke(ctx context.Context, orgId, userId string) error {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = o.os.Revoke(ctx, orgId, userId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return services.ErrNotResponsible
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Revoke", err)
+		return err
+	}
+	return nil
+}
+
+func (o *Organizations) CreateEmployee(ctx context.Context, emp *models.Employee) (*models.Employee, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.es.Create(ctx, emp)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordAlreadyExists) {
+			return nil, services.ErrUserExists
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Create", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *Organizations) GetEmployee(ctx context.Context, userId string) (*models.Employee, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.getEmployee(ctx, userId)
+}
+
+func (o *Organizations) ListEmployees(ctx context.Context, limit, offset int64) ([]*models.Employee, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.es.GetList(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method GetList", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// EditEmployee changes non empty names of employee, username can't be changed
+func (o *Organizations) EditEmployee(ctx context.Context, emp *models.Employee, userId string) (*models.Employee, error) {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := o.getEmployee(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if emp.FirstName != "" && current.FirstName != emp.FirstName {
+		current.FirstName = emp.FirstName
+		count++
+	}
+	if emp.LastName != "" && current.LastName != emp.LastName {
+		current.LastName = emp.LastName
+		count++
+	}
+	if count == 0 {
+		return current, nil
+	}
+
+	result, err := o.es.Update(ctx, current)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrUserNotFound) {
+			return nil, services.ErrNoSuchEmployee
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Update", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteEmployee refuses to delete employee having tenders, bids or audit entries
+func (o *Organizations) DeleteEmployee(ctx context.Context, userId string) error {
+	err := o.checkAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = o.es.Delete(ctx, userId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrUserNotFound) {
+			return services.ErrNoSuchEmployee
+		}
+		if errors.Is(err, store.ErrRecordInUse) {
+			return services.ErrResourceInUse
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Delete", err)
+		return err
+	}
+	return nil
+}
+
+// checkAdmin allows only users listed as admins
+func (o *Organizations) checkAdmin(ctx context.Context) error {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return services.ErrNotAuthenticated
+	}
+	if !slices.Contains(o.admins, user.Username) {
+		return services.ErrNoPermitions
+	}
+	return nil
+}
+
+func (o *Organizations) getOrganization(ctx context.Context, orgId string) (*models.Organization, error) {
+	org, err := o.os.Get(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchOrganization
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Get", err)
+		return nil, err
+	}
+	return org, nil
+}
+
+func (o *Organizations) getEmployee(ctx context.Context, userId string) (*models.Employee, error) {
+	emp, err := o.es.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrUserNotFound) {
+			return nil, services.ErrNoSuchEmployee
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		o.logger.Errorf("unexpected error: %s on method Get", err)
+		return nil, err
+	}
+	return emp, nil
+}
@@ -1,26 +1,113 @@
 package services
 
-import "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+)
+
+// Methods accepting context take the acting user from it, see reqctx.User, and optionally
+// organization the user acts on behalf of, see reqctx.Organization
+
+// AnyVersion passed as expected version disables optimistic concurrency check
+const AnyVersion int64 = -1
+
+// Orders of tender bids ranking
+const (
+	RankByPrice = "price"
+	RankByScore = "score"
+)
+
+// ActingRoles narrows roles of the user by organization ids to the organization chosen
+// for request, all of them are returned when none is chosen
+func ActingRoles(ctx context.Context, roles map[string]string) (map[string]string, error) {
+	orgId := reqctx.Organization(ctx)
+	if orgId == "" {
+		return roles, nil
+	}
+	role, ok := roles[orgId]
+	if !ok {
+		return nil, ErrNoPermitions
+	}
+	return map[string]string{orgId: role}, nil
+}
+
+// ActingOrg returns the only organization from result of ActingRoles, the user responsible
+// for several organizations has to choose one of them
+func ActingOrg(roles map[string]string) (string, error) {
+	if len(roles) != 1 {
+		return "", ErrOrganizationRequired
+	}
+	for orgId := range roles {
+		return orgId, nil
+	}
+	return "", ErrOrganizationRequired
+}
+
+type Auth interface {
+	IssueToken(ctx context.Context, username string) (string, time.Time, error)
+	Authenticate(ctx context.Context, token string) (*models.Employee, error)
+}
 
 type Tenders interface {
-	List(limit, offset int64, serviceType []string) ([]*models.Tender, error)
-	Create(tnd *models.Tender, responcible *models.Responsible) (*models.Tender, error)
-	GetByName(limit, offset int64, username string) ([]*models.Tender, error)
-	GetStat(tenderId, username string) (string, error)
-	ChangeStat(tenderId, status, username string) (*models.Tender, error)
-	Edit(tnd *models.Tender, tenderid, username string) (*models.Tender, error)
-	Rollback(tenderId, username string, version int64) (*models.Tender, error)
+	List(ctx context.Context, limit, offset int64, serviceType []string) ([]*models.Tender, error)
+	Create(ctx context.Context, tnd *models.Tender, orgId string) (*models.Tender, error)
+	GetByName(ctx context.Context, limit, offset int64) ([]*models.Tender, error)
+	GetStat(ctx context.Context, tenderId string) (string, error)
+	ChangeStat(ctx context.Context, tenderId, status string, ifMatch int64) (*models.Tender, error)
+	Edit(ctx context.Context, tnd *models.Tender, tenderid string, ifMatch int64) (*models.Tender, error)
+	Rollback(ctx context.Context, tenderId string, version int64) (*models.Tender, error)
+	GetVersions(ctx context.Context, tenderId string, limit, offset int64) ([]*models.Tender, error)
+	Diff(ctx context.Context, tenderId string, from, to int64) (*models.VersionsDiff, error)
+	SetCriteria(ctx context.Context, tenderId string, criteria []*models.Criterion) ([]*models.Criterion, error)
+	GetCriteria(ctx context.Context, tenderId string) ([]*models.Criterion, error)
 }
 
 type Bids interface {
-	Create(bid *models.Bid) (*models.Bid, error)
-	GetByName(limit, offset int64, username string) ([]*models.Bid, error)
-	GetTenderBids(limit, offset int64, tenderId, username string) ([]*models.Bid, error)
-	GetStat(bidId, username string) (string, error)
-	ChangeStat(bidId, status, username string) (*models.Bid, error)
-	Edit(bid *models.Bid, bidId, username string) (*models.Bid, error)
-	Sumbit(bidId, decision, username string) (*models.Bid, error)
-	AddFeedback(bidId, bidFeedback, username string) (*models.Bid, error)
-	Rollback(bidId string, version int64, username string) (*models.Bid, error)
-	GetReviews(tenderId, authorUsername, requesterUsername string, limit, offset int64) ([]*models.Feedback, error)
+	Create(ctx context.Context, bid *models.Bid) (*models.Bid, error)
+	GetByName(ctx context.Context, limit, offset int64) ([]*models.Bid, error)
+	GetTenderBids(ctx context.Context, limit, offset int64, tenderId, order string) ([]*models.Bid, error)
+	GetRanking(ctx context.Context, tenderId, by string) ([]*models.RankedBid, error)
+	Score(ctx context.Context, bidId string, scores []*models.Score) (*models.BidScore, error)
+	GetScore(ctx context.Context, bidId string) (*models.BidScore, error)
+	GetStat(ctx context.Context, bidId string) (string, error)
+	ChangeStat(ctx context.Context, bidId, status string, ifMatch int64) (*models.Bid, error)
+	Edit(ctx context.Context, bid *models.Bid, bidId string, ifMatch int64) (*models.Bid, error)
+	Sumbit(ctx context.Context, bidId, decision string) (*models.Bid, error)
+	AddFeedback(ctx context.Context, bidId, bidFeedback string) (*models.Bid, error)
+	Rollback(ctx context.Context, bidId string, version int64) (*models.Bid, error)
+	GetVersions(ctx context.Context, bidId string, limit, offset int64) ([]*models.Bid, error)
+	Diff(ctx context.Context, bidId string, from, to int64) (*models.VersionsDiff, error)
+	GetReviews(ctx context.Context, tenderId, authorUsername string, limit, offset int64) ([]*models.Feedback, error)
+}
+
+type Audit interface {
+	List(ctx context.Context, orgId string, limit, offset int64) ([]*models.AuditEntry, error)
+}
+
+// Attachments work with files of tenders and bids, entityType is one of models.Attachment* entities
+type Attachments interface {
+	Add(ctx context.Context, entityType, entityId string, att *models.Attachment, content io.Reader) (*models.Attachment, error)
+	List(ctx context.Context, entityType, entityId string) ([]*models.Attachment, error)
+	Open(ctx context.Context, entityType, entityId, attachmentId string) (*models.Attachment, io.ReadCloser, error)
+}
+
+// Organizations manages organizations, employees and their responsibility, available to admins only
+type Organizations interface {
+	CreateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error)
+	GetOrganization(ctx context.Context, orgId string) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, limit, offset int64) ([]*models.Organization, error)
+	EditOrganization(ctx context.Context, org *models.Organization, orgId string) (*models.Organization, error)
+	DeleteOrganization(ctx context.Context, orgId string) error
+	ListResponsibles(ctx context.Context, orgId string) ([]*models.Member, error)
+	Grant(ctx context.Context, orgId, userId, role string) error
+	Revoke(ctx context.Context, orgId, userId string) error
+	CreateEmployee(ctx context.Context, emp *models.Employee) (*models.Employee, error)
+	GetEmployee(ctx context.Context, userId string) (*models.Employee, error)
+	ListEmployees(ctx context.Context, limit, offset int64) ([]*models.Employee, error)
+	EditEmployee(ctx context.Context, emp *models.Employee, userId string) (*models.Employee, error)
+	DeleteEmployee(ctx context.Context, userId string) error
 }
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+// Shared implements steps common to services of tenders, bids and attachments,
+// unexpected errors are logged by logger of the service using it
+type Shared struct {
+	uow    store.UnitOfWork
+	logger *logrus.Entry
+}
+
+func NewShared(unitOfWork store.UnitOfWork, logger *logrus.Entry) *Shared {
+	return &Shared{
+		uow:    unitOfWork,
+		logger: logger,
+	}
+}
+
+// Subject returns the user with his roles in organizations he acts on behalf of, see ActingRoles
+func (s *Shared) Subject(ctx context.Context, rs store.Responsibles, userId string) (*policy.Subject, error) {
+	roles, err := rs.GetRoles(ctx, userId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, ErrNoPermitions
+		}
+		if interrupted := Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		s.logger.Errorf("unexpected error: %s on method GetRoles", err)
+		return nil, err
+	}
+
+	roles, err = ActingRoles(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+	return &policy.Subject{UserId: userId, Roles: roles}, nil
+}
+
+// InTx runs fn as single unit of work, errors of fn are expected to be already mapped to service errors
+func (s *Shared) InTx(ctx context.Context, fn func(repos store.Repositories) error) error {
+	err := s.uow.Do(ctx, fn)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return ErrServiceDatabaseDisconnected
+		}
+		if interrupted := Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		if errors.Is(err, store.ErrStartingTransaction) {
+			s.logger.Errorf("unexpected error: %s on method Do", err)
+			return ErrServiceDatabaseDisconnected
+		}
+		return err
+	}
+	return nil
+}
+
+// VersionConflict reports version of tender or bid written by concurrent request,
+// entityType is one of models.Audit* entities. Version is read in unit of work as reads
+// outside of it may be served by lagging replica, see store.Replicated.
+func (s *Shared) VersionConflict(ctx context.Context, entityType, entityId string) error {
+	var current int64
+	err := s.InTx(ctx, func(repos store.Repositories) error {
+		var err error
+		switch entityType {
+		case models.AuditTender:
+			var tenderCondition *models.Tender
+			tenderCondition, err = repos.Tenders.GetCondition(ctx, entityId, store.Latest)
+			if err == nil {
+				current = tenderCondition.Version
+			}
+		case models.AuditBid:
+			var bidCondition *models.Bid
+			bidCondition, err = repos.Bids.GetCondition(ctx, entityId, store.Latest)
+			if err == nil {
+				current = bidCondition.Version
+			}
+		}
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return ErrServiceDatabaseDisconnected
+			}
+			if interrupted := Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			s.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return &VersionError{Err: ErrVersionConflict, Current: current}
+}
+
+// Record writes audit entry of mutation performed by the user from ctx,
+// context without user belongs to the service's own jobs, they are recorded without actor
+func (s *Shared) Record(ctx context.Context, audit store.Audit, entry *models.AuditEntry) error {
+	user, ok := reqctx.User(ctx)
+	if ok {
+		entry.ActorId = user.Id
+	}
+	entry.RequestId = reqctx.RequestID(ctx)
+
+	err := audit.Add(ctx, entry)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return ErrServiceDatabaseDisconnected
+		}
+		if interrupted := Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		s.logger.Errorf("unexpected error: %s on method Add", err)
+		return err
+	}
+	return nil
+}
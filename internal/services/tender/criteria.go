@@ -0,0 +1,120 @@
+package tenderservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// SetCriteria replaces evaluation criteria of tender, bids scores given before are dropped
+func (t *Tender) SetCriteria(ctx context.Context, tenderId string, criteria []*models.Criterion) ([]*models.Criterion, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*models.Criterion
+	err = t.shared.InTx(ctx, func(repos store.Repositories) error {
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, tenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		if !t.pl.Allows(sub, policy.TenderEdit, policy.Tender(tenderCondition)) {
+			return services.ErrNoPermitions
+		}
+
+		if tenderCondition.Status == "Closed" {
+			return services.ErrEvaluationNotAllowed
+		}
+
+		result, err = repos.Evaluations.SetCriteria(ctx, tenderId, criteria)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method SetCriteria", err)
+			return err
+		}
+		return t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditCriteria,
+			EntityType: models.AuditTender,
+			EntityId:   tenderId,
+			OldVersion: tenderCondition.Version,
+			NewVersion: tenderCondition.Version,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCriteria is available to everyone who can see tender bids
+func (t *Tender) GetCriteria(ctx context.Context, tenderId string) ([]*models.Criterion, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tenderCondition, err := t.ts.GetCondition(ctx, tenderId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	if !t.pl.Allows(sub, policy.TenderView, policy.Tender(tenderCondition)) {
+		return nil, services.ErrNoPermitions
+	}
+
+	criteria, err := t.es.GetCriteria(ctx, tenderId)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetCriteria", err)
+		return nil, err
+	}
+	return criteria, nil
+}
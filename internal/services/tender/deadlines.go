@@ -0,0 +1,130 @@
+package tenderservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// expiredBatch limits count of expired tenders listed at once
+const expiredBatch = 100
+
+// CloseExpired writes Closed version of every published tender with deadline passed by now
+// and returns count of closed tenders. Every tender is closed in its own transaction under
+// lock, so concurrent calls from several replicas never close the same tender twice and
+// tender edited meanwhile doesn't fail closing of others.
+func (t *Tender) CloseExpired(ctx context.Context, now time.Time) (int64, error) {
+	var closed int64
+	for {
+		expired, err := t.ts.GetExpired(ctx, now, expiredBatch)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return closed, services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return closed, interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetExpired", err)
+			return closed, err
+		}
+
+		var batch int64
+		for _, tnd := range expired {
+			ok, err := t.closeExpired(ctx, tnd.Id, now)
+			if err != nil {
+				return closed + batch, err
+			}
+			if ok {
+				batch++
+			}
+		}
+		closed += batch
+
+		// skipped tenders are listed again, so listing stops once nothing was closed
+		if len(expired) < expiredBatch || batch == 0 {
+			return closed, nil
+		}
+	}
+}
+
+// closeExpired closes tender unless it's closed by another replica, edited concurrently
+// or no longer expired, false is returned then
+func (t *Tender) closeExpired(ctx context.Context, tenderId string, now time.Time) (bool, error) {
+	var ok bool
+	err := t.shared.InTx(ctx, func(repos store.Repositories) error {
+		locked, err := repos.Tenders.TryLock(ctx, tenderId)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method TryLock", err)
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		// tender is read again under lock, listed version might be outdated
+		tnd, err := repos.Tenders.GetCondition(ctx, tenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+		if tnd.Status != "Published" || !tnd.Expired(now) {
+			return nil
+		}
+
+		tnd.Status = "Closed"
+		tnd.Version += 1
+
+		result, err := repos.Tenders.UpdateCondition(ctx, tnd)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+
+		err = t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      result.OrgId,
+			Action:     models.AuditChangeStatus,
+			EntityType: models.AuditTender,
+			EntityId:   result.Id,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+		if err != nil {
+			return err
+		}
+		ok = true
+		return nil
+	})
+	if err != nil {
+		// tender edited meanwhile is closed by next run if it's still expired
+		if errors.Is(err, services.ErrVersionConflict) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ok, nil
+}
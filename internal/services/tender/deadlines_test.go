@@ -0,0 +1,103 @@
+package tenderservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+)
+
+func TestCloseExpired(t *testing.T) {
+	ctx := context.Background()
+	svc, s, f := newTestService(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	passed := now.Add(-time.Hour)
+	upcoming := now.Add(time.Hour)
+
+	expired := publishTender(t, s, f, "Expired", &passed)
+	open := publishTender(t, s, f, "Open", &upcoming)
+	draft, err := s.Tenders.Create(ctx, &models.Tender{Name: "Draft", Description: "Draft", ServType: "Delivery", Deadline: &passed}, &models.Responsible{OrgId: f.OrgId, Username: f.Responsible.Username})
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+
+	closed, err := svc.CloseExpired(ctx, now)
+	if err != nil || closed != 1 {
+		t.Fatalf("CloseExpired: expected 1 closed tender, got %d, %v", closed, err)
+	}
+
+	for _, tt := range []struct {
+		id      string
+		status  string
+		version int64
+	}{
+		{expired.Id, "Closed", 3},
+		{open.Id, "Published", 2},
+		{draft.Id, "Created", 1},
+	} {
+		got, err := s.Tenders.GetCondition(ctx, tt.id, store.Latest)
+		if err != nil || got.Status != tt.status || got.Version != tt.version {
+			t.Fatalf("GetCondition: expected %s tender of version %d, got %+v, %v", tt.status, tt.version, got, err)
+		}
+	}
+
+	entries, err := s.Audit.GetOrgList(ctx, f.OrgId, 10, 0)
+	if err != nil || len(entries) != 1 || entries[0].EntityId != expired.Id || entries[0].ActorId != "" {
+		t.Fatalf("GetOrgList: expected closing recorded without actor, got %+v, %v", entries, err)
+	}
+
+	closed, err = svc.CloseExpired(ctx, now)
+	if err != nil || closed != 0 {
+		t.Fatalf("CloseExpired: expected nothing to close, got %d, %v", closed, err)
+	}
+
+	closed, err = svc.CloseExpired(ctx, upcoming)
+	if err != nil || closed != 1 {
+		t.Fatalf("CloseExpired: expected open tender closed after its deadline, got %d, %v", closed, err)
+	}
+}
+
+func TestCloseExpiredBatches(t *testing.T) {
+	ctx := context.Background()
+	svc, s, f := newTestService(t)
+
+	passed := time.Now().Add(-time.Hour)
+	for i := 0; i < expiredBatch+1; i++ {
+		publishTender(t, s, f, "Expired", &passed)
+	}
+
+	closed, err := svc.CloseExpired(ctx, time.Now())
+	if err != nil || closed != expiredBatch+1 {
+		t.Fatalf("CloseExpired: expected %d closed tenders, got %d, %v", expiredBatch+1, closed, err)
+	}
+}
+
+func publishTender(t *testing.T, s storetest.Stores, f storetest.Fixture, name string, deadline *time.Time) *models.Tender {
+	t.Helper()
+
+	tnd, err := s.Tenders.Create(context.Background(), &models.Tender{
+		Name:        name,
+		Description: name + " description",
+		ServType:    "Delivery",
+		Deadline:    deadline,
+	}, &models.Responsible{
+		OrgId:    f.OrgId,
+		Username: f.Responsible.Username,
+	})
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+
+	tnd.OrgId = f.OrgId
+	tnd.Status = "Published"
+	tnd.Version = 2
+	tnd, err = s.Tenders.UpdateCondition(context.Background(), tnd)
+	if err != nil {
+		t.Fatalf("UpdateCondition: %s", err)
+	}
+	return tnd
+}
@@ -1,9 +1,13 @@
 package tenderservice
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
 	"github.com/sirupsen/logrus"
@@ -24,10 +28,13 @@ import (
 type Tender struct {
 	ts     store.Tenders
 	rs     store.Responsibles
+	es     store.Evaluations
+	shared *services.Shared
+	pl     *policy.Policy
 	logger *logrus.Entry
 }
 
-func New(tenderStorage store.Tenders, responsiblesStore store.Responsibles, log *logrus.Logger) *Tender {
+func New(tenderStorage store.Tenders, responsiblesStore store.Responsibles, evaluationsStore store.Evaluations, unitOfWork store.UnitOfWork, pl *policy.Policy, log *logrus.Logger) *Tender {
 	logger := log.WithFields(logrus.Fields{
 		"service": "tender",
 	})
@@ -35,93 +42,107 @@ func New(tenderStorage store.Tenders, responsiblesStore store.Responsibles, log
 	return &Tender{
 		ts:     tenderStorage,
 		rs:     responsiblesStore,
+		es:     evaluationsStore,
+		shared: services.NewShared(unitOfWork, logger),
+		pl:     pl,
 		logger: logger,
 	}
 }
 
-func (t *Tender) List(limit, offset int64, serviceType []string) ([]*models.Tender, error) {
-	tenders, err := t.ts.GetLimitedList(limit, offset, serviceType)
+func (t *Tender) List(ctx context.Context, limit, offset int64, serviceType []string) ([]*models.Tender, error) {
+	tenders, err := t.ts.GetLimitedList(ctx, limit, offset, serviceType)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		t.logger.Errorf("unexpected error: %s on method GetLimitedList", err)
 		return nil, err
 	}
 	return tenders, nil
 }
 
-func (t *Tender) Create(tnd *models.Tender, responsible *models.Responsible) (*models.Tender, error) {
-	err := t.rs.IsResponcible(responsible)
+func (t *Tender) Create(ctx context.Context, tnd *models.Tender, orgId string) (*models.Tender, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
+	}
+
+	responsible := &models.Responsible{
+		OrgId:    orgId,
+		Username: user.Username,
+	}
+
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method IsResponcible", err)
 		return nil, err
 	}
 
-	result, err := t.ts.Create(tnd, responsible)
+	if !t.pl.Allows(sub, policy.TenderCreate, &policy.Resource{OrgId: orgId}) {
+		return nil, services.ErrNoPermitions
+	}
+
+	var result *models.Tender
+	err = t.shared.InTx(ctx, func(repos store.Repositories) error {
+		result, err = repos.Tenders.Create(ctx, tnd, responsible)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method Create", err)
+			return err
+		}
+		return t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      orgId,
+			Action:     models.AuditCreate,
+			EntityType: models.AuditTender,
+			EntityId:   result.Id,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		t.logger.Errorf("unexpected error: %s on method Create", err)
 		return nil, err
 	}
 	return result, nil
 }
 
-func (t *Tender) GetByName(limit, offset int64, username string) ([]*models.Tender, error) {
-	err := t.rs.IsUserExists(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method IsUserExists", err)
-		return nil, err
+func (t *Tender) GetByName(ctx context.Context, limit, offset int64) ([]*models.Tender, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	tenders, err := t.ts.GetUserTenders(limit, offset, username)
+	tenders, err := t.ts.GetUserTenders(ctx, limit, offset, user.Username)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return nil, services.ErrServiceDatabaseDisconnected
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
 		t.logger.Errorf("unexpected error: %s on method GetUserTenders", err)
 		return nil, err
 	}
 	return tenders, nil
 }
 
-func (t *Tender) GetStat(tenderId, username string) (string, error) {
-	orgId, err := t.rs.GetOrgId(username)
+func (t *Tender) GetStat(ctx context.Context, tenderId string) (string, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return "", services.ErrNotAuthenticated
+	}
+
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return "", services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return "", services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return "", services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method GetOrgId", err)
 		return "", err
 	}
 
-	tenderCondition, err := t.ts.GetCondition(tenderId, store.Latest)
+	tenderCondition, err := t.ts.GetCondition(ctx, tenderId, store.Latest)
 	if err != nil {
 		if errors.Is(err, store.ErrConnClosed) {
 			return "", services.ErrServiceDatabaseDisconnected
@@ -129,178 +150,289 @@ func (t *Tender) GetStat(tenderId, username string) (string, error) {
 		if errors.Is(err, store.ErrRecordNotFound) {
 			return "", services.ErrNoSuchTender
 		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return "", interrupted
+		}
 		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return "", err
 	}
 
-	if tenderCondition.Status != "Published" && tenderCondition.OrgId != orgId {
+	if !t.pl.Allows(sub, policy.TenderView, policy.Tender(tenderCondition)) {
 		return "", services.ErrNoPermitions
 	}
 
 	return tenderCondition.Status, nil
 }
 
-func (t *Tender) ChangeStat(tenderId, status, username string) (*models.Tender, error) {
-	orgId, err := t.rs.GetOrgId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method GetOrgId", err)
-		return nil, err
+func (t *Tender) ChangeStat(ctx context.Context, tenderId, status string, ifMatch int64) (*models.Tender, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	tenderCondition, err := t.ts.GetCondition(tenderId, store.Latest)
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchTender
-		}
-		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
 
-	if tenderCondition.Status == status {
-		return tenderCondition, nil
-	}
-
-	if tenderCondition.OrgId != orgId {
-		return nil, services.ErrNoPermitions
-	}
-
-	tenderCondition.Status = status
-	tenderCondition.Version += 1
-
-	result, err := t.ts.UpdateCondition(tenderCondition)
+	var result *models.Tender
+	err = t.shared.InTx(ctx, func(repos store.Repositories) error {
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, tenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		// permission is checked first, so version and content of tender aren't revealed to others
+		if !t.pl.Allows(sub, policy.TenderStatus, policy.Tender(tenderCondition)) {
+			return services.ErrNoPermitions
+		}
+
+		if ifMatch != services.AnyVersion && tenderCondition.Version != ifMatch {
+			return &services.VersionError{Err: services.ErrVersionMismatch, Current: tenderCondition.Version}
+		}
+
+		if tenderCondition.Status == status {
+			result = tenderCondition
+			return nil
+		}
+
+		previous := tenderCondition.Status
+		tenderCondition.Status = status
+		err = checkTransition(previous, tenderCondition, time.Now())
+		if err != nil {
+			return err
+		}
+
+		tenderCondition.Version += 1
+
+		result, err = repos.Tenders.UpdateCondition(ctx, tenderCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditChangeStatus,
+			EntityType: models.AuditTender,
+			EntityId:   tenderId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, t.shared.VersionConflict(ctx, models.AuditTender, tenderId)
 		}
-		t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
 	return result, nil
 }
 
-func (t *Tender) Edit(tnd *models.Tender, tenderId, username string) (*models.Tender, error) {
-	orgId, err := t.rs.GetOrgId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method GetOrgId", err)
-		return nil, err
+// checkTransition tells whether tender with status from may become tender next,
+// closed tender is final and tender can't be published after its deadline
+func checkTransition(from string, next *models.Tender, now time.Time) error {
+	if from == "Closed" {
+		return services.ErrTenderClosed
 	}
-
-	tenderCondition, err := t.ts.GetCondition(tenderId, store.Latest)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchTender
-		}
-		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
-		return nil, err
-	}
-
-	if tenderCondition.OrgId != orgId {
-		return nil, services.ErrNoPermitions
+	if next.Status == "Published" && next.Expired(now) {
+		return services.ErrDeadlinePassed
 	}
+	return nil
+}
 
-	var count int
-	if tnd.Name != "" && tenderCondition.Name != tnd.Name {
-		tenderCondition.Name = tnd.Name
-		count++
+func (t *Tender) Edit(ctx context.Context, tnd *models.Tender, tenderId string, ifMatch int64) (*models.Tender, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
-	if tnd.Description != "" && tenderCondition.Description != tnd.Description {
-		tenderCondition.Description = tnd.Description
-		count++
-	}
-	if tnd.ServType != "" && tenderCondition.ServType != tnd.ServType {
-		tenderCondition.ServType = tnd.ServType
-		count++
-	}
-	if count == 0 {
-		return tenderCondition, nil
-	}
-
-	tenderCondition.Version += 1
 
-	result, err := t.ts.UpdateCondition(tenderCondition)
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
-		return nil, err
-	}
-	return result, nil
-}
-
-func (t *Tender) Rollback(tenderId, username string, version int64) (*models.Tender, error) {
-	orgId, err := t.rs.GetOrgId(username)
-	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrUserNotFound) {
-			return nil, services.ErrNoSuchUser
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoPermitions
-		}
-		t.logger.Errorf("unexpected error: %s on method GetOrgId", err)
 		return nil, err
 	}
 
-	tenderCondition, err := t.ts.GetCondition(tenderId, version)
+	var result *models.Tender
+	err = t.shared.InTx(ctx, func(repos store.Repositories) error {
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, tenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		if !t.pl.Allows(sub, policy.TenderEdit, policy.Tender(tenderCondition)) {
+			return services.ErrNoPermitions
+		}
+
+		if ifMatch != services.AnyVersion && tenderCondition.Version != ifMatch {
+			return &services.VersionError{Err: services.ErrVersionMismatch, Current: tenderCondition.Version}
+		}
+
+		var count int
+		if tnd.Name != "" && tenderCondition.Name != tnd.Name {
+			tenderCondition.Name = tnd.Name
+			count++
+		}
+		if tnd.Description != "" && tenderCondition.Description != tnd.Description {
+			tenderCondition.Description = tnd.Description
+			count++
+		}
+		if tnd.ServType != "" && tenderCondition.ServType != tnd.ServType {
+			tenderCondition.ServType = tnd.ServType
+			count++
+		}
+		if tnd.Deadline != nil && (tenderCondition.Deadline == nil || !tenderCondition.Deadline.Equal(*tnd.Deadline)) {
+			tenderCondition.Deadline = tnd.Deadline
+			count++
+		}
+		if count == 0 {
+			result = tenderCondition
+			return nil
+		}
+
+		tenderCondition.Version += 1
+
+		result, err = repos.Tenders.UpdateCondition(ctx, tenderCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditEdit,
+			EntityType: models.AuditTender,
+			EntityId:   tenderId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		if errors.Is(err, store.ErrRecordNotFound) {
-			return nil, services.ErrNoSuchTender
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, t.shared.VersionConflict(ctx, models.AuditTender, tenderId)
 		}
-		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
 		return nil, err
 	}
+	return result, nil
+}
 
-	if tenderCondition.OrgId != orgId {
-		return nil, services.ErrNoPermitions
+func (t *Tender) Rollback(ctx context.Context, tenderId string, version int64) (*models.Tender, error) {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return nil, services.ErrNotAuthenticated
 	}
 
-	latestVersion, err := t.ts.GetTenderLatestVersion(tenderId, username)
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
-		}
-		t.logger.Errorf("unexpected error: %s on method GetTenderLatestVersion", err)
 		return nil, err
 	}
 
-	tenderCondition.Version = latestVersion + 1
-
-	result, err := t.ts.UpdateCondition(tenderCondition)
+	var result *models.Tender
+	err = t.shared.InTx(ctx, func(repos store.Repositories) error {
+		tenderCondition, err := repos.Tenders.GetCondition(ctx, tenderId, version)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordNotFound) {
+				return services.ErrNoSuchTender
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		if !t.pl.Allows(sub, policy.TenderEdit, policy.Tender(tenderCondition)) {
+			return services.ErrNoPermitions
+		}
+
+		// any employee allowed to edit tender rolls it back, not only its creator
+		latest, err := repos.Tenders.GetCondition(ctx, tenderId, store.Latest)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+			return err
+		}
+
+		// restored status takes the same checks as status changed directly
+		if tenderCondition.Status != latest.Status {
+			err = checkTransition(latest.Status, tenderCondition, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		tenderCondition.Version = latest.Version + 1
+
+		result, err = repos.Tenders.UpdateCondition(ctx, tenderCondition)
+		if err != nil {
+			if errors.Is(err, store.ErrConnClosed) {
+				return services.ErrServiceDatabaseDisconnected
+			}
+			if errors.Is(err, store.ErrRecordAlreadyExists) {
+				return services.ErrVersionConflict
+			}
+			if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+				return interrupted
+			}
+			t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
+			return err
+		}
+		return t.shared.Record(ctx, repos.Audit, &models.AuditEntry{
+			OrgId:      tenderCondition.OrgId,
+			Action:     models.AuditRollback,
+			EntityType: models.AuditTender,
+			EntityId:   tenderId,
+			OldVersion: result.Version - 1,
+			NewVersion: result.Version,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrConnClosed) {
-			return nil, services.ErrServiceDatabaseDisconnected
+		if errors.Is(err, services.ErrVersionConflict) {
+			return nil, t.shared.VersionConflict(ctx, models.AuditTender, tenderId)
 		}
-		t.logger.Errorf("unexpected error: %s on method UpdateCondition", err)
 		return nil, err
 	}
 	return result, nil
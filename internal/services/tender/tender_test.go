@@ -0,0 +1,104 @@
+package tenderservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestService(t *testing.T) (*Tender, storetest.Stores, storetest.Fixture) {
+	t.Helper()
+
+	s, f := storetest.NewMemory(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return New(s.Tenders, s.Responsibles, s.Evaluations, s.UnitOfWork, policy.Default(), logger), s, f
+}
+
+func as(emp models.Employee) context.Context {
+	return reqctx.WithUser(context.Background(), &emp)
+}
+
+func TestRollbackByColleague(t *testing.T) {
+	svc, s, f := newTestService(t)
+
+	// colleague is editor who didn't create the tender
+	err := s.Organizations.SetRole(context.Background(), f.OrgId, f.Colleague.Id, policy.RoleEditor)
+	if err != nil {
+		t.Fatalf("SetRole: %s", err)
+	}
+
+	tnd, err := svc.Create(as(f.Responsible), &models.Tender{Name: "Roads", Description: "Roads repair", ServType: "Construction"}, f.OrgId)
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+	_, err = svc.Edit(as(f.Responsible), &models.Tender{Name: "Bridges"}, tnd.Id, 1)
+	if err != nil {
+		t.Fatalf("Edit: %s", err)
+	}
+
+	rolled, err := svc.Rollback(as(f.Colleague), tnd.Id, 1)
+	if err != nil || rolled.Name != "Roads" || rolled.Version != 3 {
+		t.Fatalf("Rollback: expected first version restored as 3rd, got %+v, %v", rolled, err)
+	}
+
+	entries, err := s.Audit.GetOrgList(context.Background(), f.OrgId, 1, 0)
+	if err != nil || len(entries) != 1 || entries[0].Action != models.AuditRollback || entries[0].ActorId != f.Colleague.Id {
+		t.Fatalf("GetOrgList: expected rollback by colleague recorded, got %+v, %v", entries, err)
+	}
+}
+
+func TestRollbackChecksStatus(t *testing.T) {
+	ctx := context.Background()
+	svc, s, f := newTestService(t)
+
+	tnd, err := svc.Create(as(f.Responsible), &models.Tender{Name: "Roads", Description: "Roads repair", ServType: "Construction"}, f.OrgId)
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+	for _, status := range []string{"Published", "Closed"} {
+		_, err = svc.ChangeStat(as(f.Responsible), tnd.Id, status, services.AnyVersion)
+		if err != nil {
+			t.Fatalf("ChangeStat %s: %s", status, err)
+		}
+	}
+
+	_, err = svc.Rollback(as(f.Responsible), tnd.Id, 2)
+	if !errors.Is(err, services.ErrTenderClosed) {
+		t.Fatalf("Rollback: expected closed tender not republished, got %v", err)
+	}
+	_, err = svc.ChangeStat(as(f.Responsible), tnd.Id, "Published", services.AnyVersion)
+	if !errors.Is(err, services.ErrTenderClosed) {
+		t.Fatalf("ChangeStat: expected closed tender not republished, got %v", err)
+	}
+
+	// tender was published before its deadline and unpublished after it
+	passed := time.Now().Add(-time.Hour)
+	expired := publishTender(t, s, f, "Expired", &passed)
+	expired.Status = "Created"
+	expired.Version = 3
+	_, err = s.Tenders.UpdateCondition(ctx, expired)
+	if err != nil {
+		t.Fatalf("UpdateCondition: %s", err)
+	}
+
+	_, err = svc.Rollback(as(f.Responsible), expired.Id, 2)
+	if !errors.Is(err, services.ErrDeadlinePassed) {
+		t.Fatalf("Rollback: expected expired tender not republished, got %v", err)
+	}
+
+	latest, err := s.Tenders.GetCondition(ctx, expired.Id, store.Latest)
+	if err != nil || latest.Status != "Created" || latest.Version != 3 {
+		t.Fatalf("GetCondition: expected tender left unpublished, got %+v, %v", latest, err)
+	}
+}
@@ -0,0 +1,104 @@
+package tenderservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/services"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func (t *Tender) GetVersions(ctx context.Context, tenderId string, limit, offset int64) ([]*models.Tender, error) {
+	err := t.checkHistoryAccess(ctx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := t.ts.GetVersionsList(ctx, tenderId, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetVersionsList", err)
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (t *Tender) Diff(ctx context.Context, tenderId string, from, to int64) (*models.VersionsDiff, error) {
+	err := t.checkHistoryAccess(ctx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCondition, err := t.ts.GetCondition(ctx, tenderId, from)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	toCondition, err := t.ts.GetCondition(ctx, tenderId, to)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return nil, services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return nil, interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return nil, err
+	}
+
+	return models.DiffTenders(fromCondition, toCondition), nil
+}
+
+// checkHistoryAccess allows version history only to responsibles of tender's organization
+func (t *Tender) checkHistoryAccess(ctx context.Context, tenderId string) error {
+	user, ok := reqctx.User(ctx)
+	if !ok {
+		return services.ErrNotAuthenticated
+	}
+
+	sub, err := t.shared.Subject(ctx, t.rs, user.Id)
+	if err != nil {
+		return err
+	}
+
+	tenderCondition, err := t.ts.GetCondition(ctx, tenderId, store.Latest)
+	if err != nil {
+		if errors.Is(err, store.ErrConnClosed) {
+			return services.ErrServiceDatabaseDisconnected
+		}
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return services.ErrNoSuchTender
+		}
+		if interrupted := services.Interrupted(ctx, err); interrupted != nil {
+			return interrupted
+		}
+		t.logger.Errorf("unexpected error: %s on method GetCondition", err)
+		return err
+	}
+
+	if !t.pl.Allows(sub, policy.TenderHistory, policy.Tender(tenderCondition)) {
+		return services.ErrNoPermitions
+	}
+	return nil
+}
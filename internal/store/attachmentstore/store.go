@@ -0,0 +1,93 @@
+package attachmentstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+)
+
+type AttachmentStore struct {
+	db       store.Querier
+	timeouts store.Timeouts
+}
+
+func New(db store.Querier, timeouts store.Timeouts) *AttachmentStore {
+	return &AttachmentStore{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+func (a *AttachmentStore) Add(ctx context.Context, att *models.Attachment) (*models.Attachment, error) {
+	ctx, cancel := a.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := a.db.QueryRowContext(ctx,
+		"INSERT INTO attachments (entity_type, entity_id, author_id, name, content_type, size, digest) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at;",
+		att.EntityType,
+		att.EntityId,
+		att.AuthorId,
+		att.Name,
+		att.ContentType,
+		att.Size,
+		att.Digest,
+	).Scan(&att.Id, &att.Created)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	return att, nil
+}
+
+func (a *AttachmentStore) GetList(ctx context.Context, entityType, entityId string) ([]*models.Attachment, error) {
+	ctx, cancel := a.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx,
+		"SELECT id, entity_type, entity_id, author_id, name, content_type, size, digest, created_at "+
+			"FROM attachments "+
+			"WHERE entity_type = $1 AND entity_id = $2 "+
+			"ORDER BY created_at ASC;",
+		entityType,
+		entityId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Attachment{}
+	for rows.Next() {
+		var att models.Attachment
+		err = rows.Scan(&att.Id, &att.EntityType, &att.EntityId, &att.AuthorId, &att.Name, &att.ContentType, &att.Size, &att.Digest, &att.Created)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result = append(result, &att)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (a *AttachmentStore) Get(ctx context.Context, attachmentId string) (*models.Attachment, error) {
+	ctx, cancel := a.timeouts.ForRead(ctx)
+	defer cancel()
+
+	var att models.Attachment
+	err := a.db.QueryRowContext(ctx,
+		"SELECT id, entity_type, entity_id, author_id, name, content_type, size, digest, created_at "+
+			"FROM attachments "+
+			"WHERE id = $1;",
+		attachmentId,
+	).Scan(&att.Id, &att.EntityType, &att.EntityId, &att.AuthorId, &att.Name, &att.ContentType, &att.Size, &att.Digest, &att.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, pgerr.Translate(err)
+	}
+	return &att, nil
+}
@@ -0,0 +1,89 @@
+package auditstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+)
+
+type AuditStore struct {
+	db       store.Querier
+	timeouts store.Timeouts
+}
+
+func New(db store.Querier, timeouts store.Timeouts) *AuditStore {
+	return &AuditStore{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+func (a *AuditStore) Add(ctx context.Context, entry *models.AuditEntry) error {
+	ctx, cancel := a.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := a.db.QueryRowContext(ctx,
+		"INSERT INTO audit_log (actor_id, organization_id, action, entity_type, entity_id, old_version, new_version, request_id) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at;",
+		nullActor(entry.ActorId),
+		entry.OrgId,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityId,
+		nullVersion(entry.OldVersion),
+		nullVersion(entry.NewVersion),
+		entry.RequestId,
+	).Scan(&entry.Id, &entry.Created)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	return nil
+}
+
+func (a *AuditStore) GetOrgList(ctx context.Context, orgId string, limit, offset int64) ([]*models.AuditEntry, error) {
+	ctx, cancel := a.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx,
+		"SELECT id, actor_id, organization_id, action, entity_type, entity_id, old_version, new_version, request_id, created_at "+
+			"FROM audit_log "+
+			"WHERE organization_id = $1 "+
+			"ORDER BY created_at DESC "+
+			"LIMIT $2 "+
+			"OFFSET $3;",
+		orgId,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var actorId sql.NullString
+		var oldVersion, newVersion sql.NullInt64
+		err = rows.Scan(&entry.Id, &actorId, &entry.OrgId, &entry.Action, &entry.EntityType, &entry.EntityId, &oldVersion, &newVersion, &entry.RequestId, &entry.Created)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		entry.ActorId = actorId.String
+		entry.OldVersion = oldVersion.Int64
+		entry.NewVersion = newVersion.Int64
+		result = append(result, &entry)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func nullVersion(version int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: version, Valid: version != 0}
+}
+
+func nullActor(actorId string) sql.NullString {
+	return sql.NullString{String: actorId, Valid: actorId != ""}
+}
@@ -1,47 +1,60 @@
 package bidstore
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"strings"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+	"github.com/lib/pq"
 )
 
 type BidStore struct {
-	db    *sql.DB
-	stats map[string]string
+	db       store.Querier
+	timeouts store.Timeouts
+	stats    map[string]string
+	orders   map[string]string
 }
 
-func New(db *sql.DB) *BidStore {
+func New(db store.Querier, timeouts store.Timeouts) *BidStore {
 	return &BidStore{
-		db: db,
+		db:       db,
+		timeouts: timeouts,
 		stats: map[string]string{
 			"CREATED":   "Created",
 			"PUBLISHED": "Published",
 			"CANCELED":  "Canceled",
+			"APPROVED":  "Approved",
+			"REJECTED":  "Rejected",
+		},
+		orders: map[string]string{
+			store.BidsByName:     "bv.name ASC",
+			store.BidsByPrice:    "bv.currency ASC NULLS LAST, bv.amount ASC NULLS LAST, bv.name ASC",
+			store.BidsByDelivery: "bv.delivery_days ASC NULLS LAST, bv.name ASC",
 		},
 	}
 }
 
-func (b *BidStore) Create(bid *models.Bid, orgId string) (*models.Bid, error) {
+func (b *BidStore) Create(ctx context.Context, bid *models.Bid, orgId string) (*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForWrite(ctx)
+	defer cancel()
+
 	var err error
 	if bid.AuthorType == "Organization" {
-		err = b.db.QueryRow(
+		err = b.db.QueryRowContext(ctx,
 			"INSERT INTO bids (tender_id, author_type, organization_id) VALUES ($1, $2, $3) RETURNING id;",
 			bid.TenderId,
 			bid.AuthorType,
 			bid.AuthorId,
 		).Scan(&bid.Id)
 		if err != nil {
-			if strings.Contains(err.Error(), "no such host") {
-				return nil, store.ErrConnClosed
-			}
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 	} else {
-		err = b.db.QueryRow(
+		err = b.db.QueryRowContext(ctx,
 			"INSERT INTO bids (tender_id, author_type, organization_id, user_id) VALUES ($1, $2, $3, $4) RETURNING id;",
 			bid.TenderId,
 			bid.AuthorType,
@@ -49,33 +62,37 @@ func (b *BidStore) Create(bid *models.Bid, orgId string) (*models.Bid, error) {
 			bid.AuthorId,
 		).Scan(&bid.Id)
 		if err != nil {
-			if strings.Contains(err.Error(), "no such host") {
-				return nil, store.ErrConnClosed
-			}
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 	}
 
-	err = b.db.QueryRow(
-		"INSERT INTO bids_versions (bid_id, name, description, status) VALUES ($1, $2, $3, 'CREATED') RETURNING created_at, version, status;",
+	err = b.db.QueryRowContext(ctx,
+		"INSERT INTO bids_versions (bid_id, name, description, status, amount, currency, delivery_days) VALUES ($1, $2, $3, 'CREATED', $4, NULLIF($5, ''), $6) RETURNING created_at, version, status;",
 		bid.Id,
 		bid.Name,
 		bid.Description,
+		bid.Amount,
+		bid.Currency,
+		bid.DeliveryDays,
 	).Scan(&bid.Created, &bid.Version, &bid.Status)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	bid.Status = b.stats[bid.Status]
+	bid.OrgId = orgId
+	if bid.AuthorType == "Organization" {
+		bid.OrgId = bid.AuthorId
+	}
 
 	return bid, nil
 }
 
-func (b *BidStore) GetUserList(limit, offset int64, userId string) ([]*models.Bid, error) {
-	rows, err := b.db.Query(
-		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, b.user_id, bv.version, bv.created_at "+
+func (b *BidStore) GetUserList(ctx context.Context, limit, offset int64, userId string) ([]*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, b.user_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days "+
 			"FROM bids_versions bv "+
 			"INNER JOIN ( "+
 			"SELECT bid_id, MAX(version) AS latest_version "+
@@ -96,31 +113,32 @@ func (b *BidStore) GetUserList(limit, offset int64, userId string) ([]*models.Bi
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
+	defer rows.Close()
 
 	result := []*models.Bid{}
 	for rows.Next() {
 		var bid models.Bid
-		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created)
+		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		bid.Status = b.stats[bid.Status]
 		result = append(result, &bid)
 	}
-	return result, nil
+	return result, pgerr.Translate(rows.Err())
 }
 
-func (b *BidStore) GetCondition(bidId string, version int64) (*models.Bid, error) {
+func (b *BidStore) GetCondition(ctx context.Context, bidId string, version int64) (*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var err error
 	var bid models.Bid
 	if version == store.Latest {
-		err = b.db.QueryRow(
-			"SELECT bv.bid_id, b.tender_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at "+
+		err = b.db.QueryRowContext(ctx,
+			"SELECT bv.bid_id, b.tender_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days, b.organization_id "+
 				"FROM bids_versions bv "+
 				"INNER JOIN ( "+
 				"SELECT bid_id, MAX(version) AS latest_version "+
@@ -130,33 +148,67 @@ func (b *BidStore) GetCondition(bidId string, version int64) (*models.Bid, error
 				"INNER JOIN bids b ON b.id = bv.bid_id "+
 				"WHERE bv.bid_id = $1;",
 			bidId,
-		).Scan(&bid.Id, &bid.TenderId, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created)
+		).Scan(&bid.Id, &bid.TenderId, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays, &bid.OrgId)
 	} else {
-		err = b.db.QueryRow(
-			"SELECT bv.bid_id, b.tender_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at "+
+		err = b.db.QueryRowContext(ctx,
+			"SELECT bv.bid_id, b.tender_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days, b.organization_id "+
 				"FROM bids_versions bv "+
 				"INNER JOIN bids b ON b.id = bv.bid_id "+
-				"WHERE b.bid_id = $1 AND bv.version = $2;",
+				"WHERE b.id = $1 AND bv.version = $2;",
 			bidId,
 			version,
-		).Scan(&bid.Id, &bid.TenderId, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created)
+		).Scan(&bid.Id, &bid.TenderId, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays, &bid.OrgId)
 	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	bid.Status = b.stats[bid.Status]
 	return &bid, nil
 }
 
-func (b *BidStore) GetBidLatestVersion(bidId string) (int64, error) {
+func (b *BidStore) GetVersionsList(ctx context.Context, bidId string, limit, offset int64) ([]*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.bid_id, b.tender_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days, b.organization_id "+
+			"FROM bids_versions bv "+
+			"INNER JOIN bids b ON b.id = bv.bid_id "+
+			"WHERE b.id = $1 "+
+			"ORDER BY bv.version ASC "+
+			"LIMIT $2 "+
+			"OFFSET $3;",
+		bidId,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Bid{}
+	for rows.Next() {
+		var bid models.Bid
+		err = rows.Scan(&bid.Id, &bid.TenderId, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays, &bid.OrgId)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		bid.Status = b.stats[bid.Status]
+		result = append(result, &bid)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (b *BidStore) GetBidLatestVersion(ctx context.Context, bidId string) (int64, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var version int64
-	err := b.db.QueryRow(
+	err := b.db.QueryRowContext(ctx,
 		"SELECT bv.version "+
 			"FROM bids_versions bv "+
 			"INNER JOIN ( "+
@@ -172,40 +224,48 @@ func (b *BidStore) GetBidLatestVersion(bidId string) (int64, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return -1, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return -1, store.ErrConnClosed
-		}
-		return -1, err
+		return -1, pgerr.Translate(err)
 	}
 
 	return version, nil
 }
 
-func (b *BidStore) UpdateCondition(newCondition *models.Bid) (*models.Bid, error) {
+func (b *BidStore) UpdateCondition(ctx context.Context, newCondition *models.Bid) (*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForWrite(ctx)
+	defer cancel()
+
 	newCondition.Status = strings.ToUpper(newCondition.Status)
 
-	_, err := b.db.Exec(
-		"INSERT INTO bids_versions (bid_id, name, description, status, version, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+	_, err := b.db.ExecContext(ctx,
+		"INSERT INTO bids_versions (bid_id, name, description, status, version, created_at, amount, currency, delivery_days) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)",
 		newCondition.Id,
 		newCondition.Name,
 		newCondition.Description,
 		newCondition.Status,
 		newCondition.Version,
 		newCondition.Created,
+		newCondition.Amount,
+		newCondition.Currency,
+		newCondition.DeliveryDays,
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	newCondition.Status = b.stats[newCondition.Status]
 	return newCondition, nil
 }
 
-func (b *BidStore) GetTenderList(limit, offset int64, tenderId, orgId string) ([]*models.Bid, error) {
-	rows, err := b.db.Query(
-		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at "+
+func (b *BidStore) GetTenderList(ctx context.Context, limit, offset int64, tenderId string, orgIds []string, order string) ([]*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	orderBy, ok := b.orders[order]
+	if !ok {
+		orderBy = b.orders[store.BidsByName]
+	}
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days "+
 			"FROM bids_versions AS bv "+
 			"INNER JOIN ( "+
 			"SELECT bid_id, MAX(version) AS latest_version "+
@@ -213,12 +273,12 @@ func (b *BidStore) GetTenderList(limit, offset int64, tenderId, orgId string) ([
 			"GROUP BY bid_id "+
 			") lv ON bv.bid_id = lv.bid_id AND bv.version = lv.latest_version "+
 			"INNER JOIN bids AS b ON b.id = bv.bid_id "+
-			"WHERE b.tender_id = $1 AND (bv.status = 'PUBLISHED' OR b.organization_id = $2) "+
-			"ORDER BY bv.name ASC "+
+			"WHERE b.tender_id = $1 AND (bv.status = 'PUBLISHED' OR b.organization_id = ANY($2)) "+
+			"ORDER BY "+orderBy+" "+
 			"LIMIT $3 "+
 			"OFFSET $4",
 		tenderId,
-		orgId,
+		pq.Array(orgIds),
 		limit,
 		offset,
 	)
@@ -227,52 +287,124 @@ func (b *BidStore) GetTenderList(limit, offset int64, tenderId, orgId string) ([
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Bid{}
+	for rows.Next() {
+		var bid models.Bid
+		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays)
+		if err != nil {
+			return nil, pgerr.Translate(err)
 		}
-		return nil, err
+		bid.Status = b.stats[bid.Status]
+		result = append(result, &bid)
 	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (b *BidStore) GetRanking(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days "+
+			"FROM bids_versions AS bv "+
+			"INNER JOIN ( "+
+			"SELECT bid_id, MAX(version) AS latest_version "+
+			"FROM bids_versions "+
+			"GROUP BY bid_id "+
+			") lv ON bv.bid_id = lv.bid_id AND bv.version = lv.latest_version "+
+			"INNER JOIN bids AS b ON b.id = bv.bid_id "+
+			"WHERE b.tender_id = $1 AND bv.status = 'PUBLISHED' AND bv.amount IS NOT NULL "+
+			"ORDER BY bv.currency ASC, bv.amount ASC, bv.delivery_days ASC NULLS LAST, bv.created_at ASC;",
+		tenderId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
 
 	result := []*models.Bid{}
 	for rows.Next() {
 		var bid models.Bid
-		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created)
+		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		bid.Status = b.stats[bid.Status]
 		result = append(result, &bid)
 	}
-	return result, nil
+	return result, pgerr.Translate(rows.Err())
 }
 
-func (b *BidStore) AddFeedback(bidId, userId, feedback string) error {
-	_, err := b.db.Exec(
+func (b *BidStore) GetPublishedList(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.bid_id, bv.name, bv.description, bv.status, b.author_type, CASE WHEN b.author_type = 'User' THEN b.user_id ELSE b.organization_id END AS author_id, bv.version, bv.created_at, bv.amount, COALESCE(bv.currency, ''), bv.delivery_days "+
+			"FROM bids_versions AS bv "+
+			"INNER JOIN ( "+
+			"SELECT bid_id, MAX(version) AS latest_version "+
+			"FROM bids_versions "+
+			"GROUP BY bid_id "+
+			") lv ON bv.bid_id = lv.bid_id AND bv.version = lv.latest_version "+
+			"INNER JOIN bids AS b ON b.id = bv.bid_id "+
+			"WHERE b.tender_id = $1 AND bv.status = 'PUBLISHED' "+
+			"ORDER BY bv.created_at ASC;",
+		tenderId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Bid{}
+	for rows.Next() {
+		var bid models.Bid
+		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.Created, &bid.Amount, &bid.Currency, &bid.DeliveryDays)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		bid.Status = b.stats[bid.Status]
+		result = append(result, &bid)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (b *BidStore) AddFeedback(ctx context.Context, bidId, userId, feedback string) error {
+	ctx, cancel := b.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	_, err := b.db.ExecContext(ctx,
 		"INSERT INTO feedbacks (bid_id, user_id, feedback) VALUES ($1, $2, $3);",
 		bidId,
 		userId,
 		feedback,
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return store.ErrConnClosed
-		}
-		return err
+		return pgerr.Translate(err)
 	}
 	return nil
 }
 
-func (b *BidStore) GetFeedbacks(tenderId, authorUserId string, limit, offset int64) ([]*models.Feedback, error) {
-	rows, err := b.db.Query(
+func (b *BidStore) GetFeedbacks(ctx context.Context, tenderId, authorUsername string, limit, offset int64) ([]*models.Feedback, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
 		"SELECT f.id, f.feedback, f.created_at "+
 			"FROM feedbacks AS f "+
 			"INNER JOIN bids AS b ON f.bid_id = b.id "+
-			"INNER JOIN bids_versions bv ON b.id = bv.bid_id "+
-			"INNER JOIN tenders AS t ON b.tender_id = t.id "+
-			"WHERE bv.status = 'PUBLISHED' AND t.id = $1 "+
-			"ORDER BY f.feedback ASC "+
-			"LIMIT $2 "+
-			"OFFSET $3;",
+			"INNER JOIN employee AS e ON b.user_id = e.id "+
+			"WHERE b.author_type = 'User' AND e.username = $1 "+
+			"AND EXISTS (SELECT 1 FROM bids AS tb WHERE tb.tender_id = $2 AND tb.user_id = e.id) "+
+			"ORDER BY f.created_at ASC "+
+			"LIMIT $3 "+
+			"OFFSET $4;",
+		authorUsername,
 		tenderId,
 		limit,
 		offset,
@@ -282,20 +414,105 @@ func (b *BidStore) GetFeedbacks(tenderId, authorUserId string, limit, offset int
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
+	defer rows.Close()
 
 	result := []*models.Feedback{}
 	for rows.Next() {
 		var feed models.Feedback
 		err = rows.Scan(&feed.Id, &feed.Desc, &feed.Created)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		result = append(result, &feed)
 	}
-	return result, nil
+	return result, pgerr.Translate(rows.Err())
+}
+
+// Lock takes row lock of bid held until end of transaction, outside of transaction it's released at once
+func (b *BidStore) Lock(ctx context.Context, bidId string) error {
+	ctx, cancel := b.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	var id string
+	err := b.db.QueryRowContext(ctx,
+		"SELECT id FROM bids WHERE id = $1 FOR UPDATE;",
+		bidId,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.ErrRecordNotFound
+		}
+		return pgerr.Translate(err)
+	}
+	return nil
+}
+
+func (b *BidStore) AddDecision(ctx context.Context, bidId, userId, decision string) error {
+	ctx, cancel := b.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	_, err := b.db.ExecContext(ctx,
+		"INSERT INTO bid_decisions (bid_id, user_id, decision) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (bid_id, user_id) DO UPDATE SET decision = EXCLUDED.decision, created_at = CURRENT_TIMESTAMP;",
+		bidId,
+		userId,
+		decision,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	return nil
+}
+
+func (b *BidStore) GetDecisionsCount(ctx context.Context, bidId string) (int64, int64, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	var approved, rejected int64
+	err := b.db.QueryRowContext(ctx,
+		"SELECT "+
+			"COUNT(*) FILTER (WHERE decision = 'Approved'), "+
+			"COUNT(*) FILTER (WHERE decision = 'Rejected') "+
+			"FROM bid_decisions "+
+			"WHERE bid_id = $1;",
+		bidId,
+	).Scan(&approved, &rejected)
+	if err != nil {
+		return 0, 0, pgerr.Translate(err)
+	}
+	return approved, rejected, nil
+}
+
+func (b *BidStore) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	ctx, cancel := b.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT bv.status, COUNT(*) "+
+			"FROM bids_versions AS bv "+
+			"INNER JOIN ( "+
+			"SELECT bid_id, MAX(version) AS latest_version "+
+			"FROM bids_versions "+
+			"GROUP BY bid_id "+
+			") AS lv ON bv.bid_id = lv.bid_id AND bv.version = lv.latest_version "+
+			"GROUP BY bv.status;",
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := map[string]int64{}
+	for rows.Next() {
+		var status string
+		var count int64
+		err = rows.Scan(&status, &count)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result[b.stats[status]] = count
+	}
+	return result, pgerr.Translate(rows.Err())
 }
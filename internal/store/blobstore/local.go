@@ -0,0 +1,92 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+var digestFormat = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// LocalStore keeps blobs as files of local directory, blob with digest "ab12..."
+// lives at "<dir>/ab/ab12..." so no directory grows too large
+type LocalStore struct {
+	dir string
+}
+
+// NewLocal creates directory when it doesn't exist
+func NewLocal(dir string) (*LocalStore, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &LocalStore{
+		dir: dir,
+	}, nil
+}
+
+func (l *LocalStore) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	// content is written to temporary file first, its name is known only after whole content is hashed
+	tmp, err := os.CreateTemp(l.dir, "upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		tmp.Close()
+		return "", 0, fmt.Errorf("write blob: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return "", 0, fmt.Errorf("write blob: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	path := l.path(digest)
+	_, err = os.Stat(path)
+	if err == nil {
+		// same content is already stored
+		return digest, size, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return "", 0, fmt.Errorf("create blob dir: %w", err)
+	}
+	// rename is atomic, so concurrent puts of same content leave one complete file
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return "", 0, fmt.Errorf("save blob: %w", err)
+	}
+	return digest, size, nil
+}
+
+func (l *LocalStore) Open(ctx context.Context, digest string) (io.ReadCloser, error) {
+	if !digestFormat.MatchString(digest) {
+		return nil, store.ErrRecordNotFound
+	}
+
+	f, err := os.Open(l.path(digest))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("open blob: %w", err)
+	}
+	return f, nil
+}
+
+func (l *LocalStore) path(digest string) string {
+	return filepath.Join(l.dir, digest[:2], digest)
+}
@@ -0,0 +1,155 @@
+package employeestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+)
+
+type EmployeeStore struct {
+	db       store.Querier
+	timeouts store.Timeouts
+}
+
+func New(db store.Querier, timeouts store.Timeouts) *EmployeeStore {
+	return &EmployeeStore{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+func (e *EmployeeStore) Create(ctx context.Context, emp *models.Employee) (*models.Employee, error) {
+	ctx, cancel := e.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := e.db.QueryRowContext(ctx,
+		"INSERT INTO employee (username, first_name, last_name) VALUES ($1, $2, $3) RETURNING id;",
+		emp.Username,
+		emp.FirstName,
+		emp.LastName,
+	).Scan(&emp.Id)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	return emp, nil
+}
+
+func (e *EmployeeStore) Get(ctx context.Context, userId string) (*models.Employee, error) {
+	ctx, cancel := e.timeouts.ForRead(ctx)
+	defer cancel()
+
+	emp, err := scanEmployee(e.db.QueryRowContext(ctx,
+		"SELECT id, username, first_name, last_name FROM employee WHERE id = $1;",
+		userId,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrUserNotFound
+		}
+		return nil, pgerr.Translate(err)
+	}
+	return emp, nil
+}
+
+func (e *EmployeeStore) GetList(ctx context.Context, limit, offset int64) ([]*models.Employee, error) {
+	ctx, cancel := e.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := e.db.QueryContext(ctx,
+		"SELECT id, username, first_name, last_name "+
+			"FROM employee "+
+			"ORDER BY username ASC "+
+			"LIMIT $1 "+
+			"OFFSET $2;",
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Employee{}
+	for rows.Next() {
+		emp, err := scanEmployee(rows)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result = append(result, emp)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (e *EmployeeStore) Update(ctx context.Context, emp *models.Employee) (*models.Employee, error) {
+	ctx, cancel := e.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	res, err := e.db.ExecContext(ctx,
+		"UPDATE employee SET first_name = $2, last_name = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1;",
+		emp.Id,
+		emp.FirstName,
+		emp.LastName,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, store.ErrUserNotFound
+	}
+	return e.Get(ctx, emp.Id)
+}
+
+func (e *EmployeeStore) Delete(ctx context.Context, userId string) error {
+	ctx, cancel := e.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	_, err := e.Get(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	// tables of tenders and bids cascade deletes of employee, so employee
+	// with any history is kept
+	res, err := e.db.ExecContext(ctx,
+		"DELETE FROM employee AS e WHERE e.id = $1 "+
+			"AND NOT EXISTS (SELECT 1 FROM tenders WHERE username = e.username) "+
+			"AND NOT EXISTS (SELECT 1 FROM bids WHERE user_id = e.id) "+
+			"AND NOT EXISTS (SELECT 1 FROM audit_log WHERE actor_id = e.id);",
+		userId,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordInUse
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEmployee(row scanner) (*models.Employee, error) {
+	var emp models.Employee
+	var firstName, lastName sql.NullString
+	err := row.Scan(&emp.Id, &emp.Username, &firstName, &lastName)
+	if err != nil {
+		return nil, err
+	}
+	emp.FirstName = firstName.String
+	emp.LastName = lastName.String
+	return &emp, nil
+}
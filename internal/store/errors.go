@@ -8,4 +8,8 @@ var (
 	ErrStartingTransaction = errors.New("unable to start transaction")
 	ErrConnClosed          = errors.New("connection closed")
 	ErrUserNotFound        = errors.New("no such username in db")
+	ErrRecordInUse         = errors.New("record is referenced by other records")
+	ErrReferenceNotFound   = errors.New("referenced record doesn't exist")
+	ErrRetryable           = errors.New("transaction conflicted with concurrent one, retry")
+	ErrTimeout             = errors.New("operation timed out")
 )
@@ -0,0 +1,126 @@
+package evaluationstore
+
+import (
+	"context"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+)
+
+type EvaluationStore struct {
+	db       store.Querier
+	timeouts store.Timeouts
+}
+
+func New(db store.Querier, timeouts store.Timeouts) *EvaluationStore {
+	return &EvaluationStore{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+// SetCriteria has to be called inside unit of work, otherwise failed insert leaves tender without criteria
+func (e *EvaluationStore) SetCriteria(ctx context.Context, tenderId string, criteria []*models.Criterion) ([]*models.Criterion, error) {
+	ctx, cancel := e.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	_, err := e.db.ExecContext(ctx,
+		"DELETE FROM tender_criteria WHERE tender_id = $1;",
+		tenderId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+
+	for i, c := range criteria {
+		c.TenderId = tenderId
+		err = e.db.QueryRowContext(ctx,
+			"INSERT INTO tender_criteria (tender_id, name, weight, position) VALUES ($1, $2, $3, $4) RETURNING id;",
+			tenderId,
+			c.Name,
+			c.Weight,
+			i,
+		).Scan(&c.Id)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+	}
+	return criteria, nil
+}
+
+func (e *EvaluationStore) GetCriteria(ctx context.Context, tenderId string) ([]*models.Criterion, error) {
+	ctx, cancel := e.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := e.db.QueryContext(ctx,
+		"SELECT id, tender_id, name, weight "+
+			"FROM tender_criteria "+
+			"WHERE tender_id = $1 "+
+			"ORDER BY position ASC;",
+		tenderId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Criterion{}
+	for rows.Next() {
+		var c models.Criterion
+		err = rows.Scan(&c.Id, &c.TenderId, &c.Name, &c.Weight)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result = append(result, &c)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (e *EvaluationStore) SetScore(ctx context.Context, score *models.Score) error {
+	ctx, cancel := e.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := e.db.QueryRowContext(ctx,
+		"INSERT INTO bid_scores (bid_id, criterion_id, evaluator_id, value) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (bid_id, criterion_id, evaluator_id) DO UPDATE SET value = EXCLUDED.value, created_at = CURRENT_TIMESTAMP "+
+			"RETURNING created_at;",
+		score.BidId,
+		score.CriterionId,
+		score.EvaluatorId,
+		score.Value,
+	).Scan(&score.Created)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	return nil
+}
+
+func (e *EvaluationStore) GetScores(ctx context.Context, tenderId string) ([]*models.Score, error) {
+	ctx, cancel := e.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := e.db.QueryContext(ctx,
+		"SELECT s.bid_id, s.criterion_id, s.evaluator_id, s.value, s.created_at "+
+			"FROM bid_scores AS s "+
+			"INNER JOIN tender_criteria AS c ON c.id = s.criterion_id "+
+			"WHERE c.tender_id = $1 "+
+			"ORDER BY s.created_at ASC;",
+		tenderId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Score{}
+	for rows.Next() {
+		var s models.Score
+		err = rows.Scan(&s.BidId, &s.CriterionId, &s.EvaluatorId, &s.Value, &s.Created)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result = append(result, &s)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
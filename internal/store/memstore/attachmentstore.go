@@ -0,0 +1,59 @@
+package memstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/google/uuid"
+)
+
+type AttachmentStore struct {
+	db *DB
+}
+
+func NewAttachmentStore(db *DB) *AttachmentStore {
+	return &AttachmentStore{
+		db: db,
+	}
+}
+
+func (a *AttachmentStore) Add(ctx context.Context, att *models.Attachment) (*models.Attachment, error) {
+	a.db.mu.Lock()
+	defer a.db.mu.Unlock()
+
+	att.Id = uuid.New().String()
+	att.Created = time.Now().UTC()
+
+	stored := *att
+	a.db.attachments = append(a.db.attachments, &stored)
+	return att, nil
+}
+
+func (a *AttachmentStore) GetList(ctx context.Context, entityType, entityId string) ([]*models.Attachment, error) {
+	a.db.mu.RLock()
+	defer a.db.mu.RUnlock()
+
+	result := []*models.Attachment{}
+	for _, att := range a.db.attachments {
+		if att.EntityType == entityType && att.EntityId == entityId {
+			stored := *att
+			result = append(result, &stored)
+		}
+	}
+	return result, nil
+}
+
+func (a *AttachmentStore) Get(ctx context.Context, attachmentId string) (*models.Attachment, error) {
+	a.db.mu.RLock()
+	defer a.db.mu.RUnlock()
+
+	for _, att := range a.db.attachments {
+		if att.Id == attachmentId {
+			stored := *att
+			return &stored, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
@@ -0,0 +1,48 @@
+package memstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type AuditStore struct {
+	db *DB
+}
+
+func NewAuditStore(db *DB) *AuditStore {
+	return &AuditStore{
+		db: db,
+	}
+}
+
+func (a *AuditStore) Add(ctx context.Context, entry *models.AuditEntry) error {
+	a.db.mu.Lock()
+	defer a.db.mu.Unlock()
+
+	entry.Id = uuid.New().String()
+	entry.Created = time.Now().UTC()
+
+	stored := *entry
+	a.db.audit = append(a.db.audit, &stored)
+	return nil
+}
+
+func (a *AuditStore) GetOrgList(ctx context.Context, orgId string, limit, offset int64) ([]*models.AuditEntry, error) {
+	a.db.mu.RLock()
+	defer a.db.mu.RUnlock()
+
+	// entries are stored in creation order, newest go first
+	entries := []*models.AuditEntry{}
+	for i := len(a.db.audit) - 1; i >= 0; i-- {
+		if a.db.audit[i].OrgId == orgId {
+			entry := *a.db.audit[i]
+			entries = append(entries, &entry)
+		}
+	}
+
+	from, to := paginate(len(entries), limit, offset)
+	return entries[from:to], nil
+}
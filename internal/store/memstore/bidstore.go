@@ -0,0 +1,375 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/google/uuid"
+)
+
+type BidStore struct {
+	db *DB
+}
+
+func NewBidStore(db *DB) *BidStore {
+	return &BidStore{
+		db: db,
+	}
+}
+
+func (b *BidStore) Create(ctx context.Context, bid *models.Bid, orgId string) (*models.Bid, error) {
+	b.db.mu.Lock()
+	defer b.db.mu.Unlock()
+
+	if _, ok := b.db.tenders[bid.TenderId]; !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	bid.Id = uuid.New().String()
+	bid.Status = "Created"
+	bid.Version = 1
+	bid.Created = time.Now().UTC()
+
+	stored := &storedBid{
+		tenderId:   bid.TenderId,
+		authorType: bid.AuthorType,
+	}
+	if bid.AuthorType == "Organization" {
+		stored.orgId = bid.AuthorId
+	} else {
+		stored.orgId = orgId
+		stored.userId = bid.AuthorId
+	}
+	bid.OrgId = stored.orgId
+	stored.versions = []models.Bid{*bid}
+	b.db.bids[bid.Id] = stored
+
+	return bid, nil
+}
+
+func (b *BidStore) GetUserList(ctx context.Context, limit, offset int64, userId string) ([]*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	result := []*models.Bid{}
+	for _, stored := range b.db.bids {
+		if stored.authorType != "User" || stored.userId != userId {
+			continue
+		}
+		latest := stored.latest()
+		result = append(result, &latest)
+	}
+	return pageBids(result, limit, offset, store.BidsByName), nil
+}
+
+func (b *BidStore) GetTenderList(ctx context.Context, limit, offset int64, tenderId string, orgIds []string, order string) ([]*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	result := []*models.Bid{}
+	for _, stored := range b.db.bids {
+		if stored.tenderId != tenderId {
+			continue
+		}
+		latest := stored.latest()
+		if latest.Status != "Published" && !slices.Contains(orgIds, stored.orgId) {
+			continue
+		}
+		result = append(result, &latest)
+	}
+	return pageBids(result, limit, offset, order), nil
+}
+
+func (b *BidStore) GetRanking(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	result := []*models.Bid{}
+	for _, stored := range b.db.bids {
+		if stored.tenderId != tenderId {
+			continue
+		}
+		latest := stored.latest()
+		if latest.Status != "Published" || latest.Amount == nil {
+			continue
+		}
+		result = append(result, &latest)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		x, y := result[i], result[j]
+		if x.Currency != y.Currency {
+			return x.Currency < y.Currency
+		}
+		if *x.Amount != *y.Amount {
+			return *x.Amount < *y.Amount
+		}
+		if c := compareNullLast(x.DeliveryDays, y.DeliveryDays); c != 0 {
+			return c < 0
+		}
+		return x.Created.Before(y.Created)
+	})
+	return result, nil
+}
+
+func (b *BidStore) GetPublishedList(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	result := []*models.Bid{}
+	for _, stored := range b.db.bids {
+		if stored.tenderId != tenderId {
+			continue
+		}
+		latest := stored.latest()
+		if latest.Status != "Published" {
+			continue
+		}
+		result = append(result, &latest)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Created.Before(result[j].Created)
+	})
+	return result, nil
+}
+
+func (b *BidStore) GetCondition(ctx context.Context, bidId string, version int64) (*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	stored, ok := b.db.bids[bidId]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	if version == store.Latest {
+		latest := stored.latest()
+		return &latest, nil
+	}
+
+	for _, v := range stored.versions {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (b *BidStore) GetVersionsList(ctx context.Context, bidId string, limit, offset int64) ([]*models.Bid, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	stored, ok := b.db.bids[bidId]
+	if !ok {
+		return []*models.Bid{}, nil
+	}
+
+	versions := make([]*models.Bid, 0, len(stored.versions))
+	for _, v := range stored.versions {
+		version := v
+		versions = append(versions, &version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	from, to := paginate(len(versions), limit, offset)
+	return versions[from:to], nil
+}
+
+func (b *BidStore) GetBidLatestVersion(ctx context.Context, bidId string) (int64, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	stored, ok := b.db.bids[bidId]
+	if !ok {
+		return -1, store.ErrRecordNotFound
+	}
+	return stored.latest().Version, nil
+}
+
+func (b *BidStore) UpdateCondition(ctx context.Context, newCondition *models.Bid) (*models.Bid, error) {
+	b.db.mu.Lock()
+	defer b.db.mu.Unlock()
+
+	stored, ok := b.db.bids[newCondition.Id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	for _, v := range stored.versions {
+		if v.Version == newCondition.Version {
+			return nil, store.ErrRecordAlreadyExists
+		}
+	}
+	stored.versions = append(stored.versions, *newCondition)
+
+	return newCondition, nil
+}
+
+func (b *BidStore) AddFeedback(ctx context.Context, bidId, userId, feedbackText string) error {
+	b.db.mu.Lock()
+	defer b.db.mu.Unlock()
+
+	if _, ok := b.db.bids[bidId]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	b.db.feedbacks = append(b.db.feedbacks, &feedback{
+		id:      uuid.New().String(),
+		bidId:   bidId,
+		userId:  userId,
+		text:    feedbackText,
+		created: time.Now().UTC(),
+	})
+	return nil
+}
+
+func (b *BidStore) GetFeedbacks(ctx context.Context, tenderId, authorUsername string, limit, offset int64) ([]*models.Feedback, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	author, ok := b.db.employeeByUsername(authorUsername)
+	if !ok {
+		return []*models.Feedback{}, nil
+	}
+
+	// author has to take part in the tender
+	participates := false
+	for _, stored := range b.db.bids {
+		if stored.tenderId == tenderId && stored.authorType == "User" && stored.userId == author.Id {
+			participates = true
+			break
+		}
+	}
+	if !participates {
+		return []*models.Feedback{}, nil
+	}
+
+	result := []*models.Feedback{}
+	for _, f := range b.db.feedbacks {
+		stored := b.db.bids[f.bidId]
+		if stored.authorType != "User" || stored.userId != author.Id {
+			continue
+		}
+		result = append(result, &models.Feedback{
+			Id:      f.id,
+			Desc:    f.text,
+			Created: f.created,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Created.Before(result[j].Created)
+	})
+	start, end := paginate(len(result), limit, offset)
+	return result[start:end], nil
+}
+
+// Lock only checks bid exists, units of work of memstore don't run concurrently
+func (b *BidStore) Lock(ctx context.Context, bidId string) error {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	if _, ok := b.db.bids[bidId]; !ok {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (b *BidStore) AddDecision(ctx context.Context, bidId, userId, decision string) error {
+	b.db.mu.Lock()
+	defer b.db.mu.Unlock()
+
+	if _, ok := b.db.bids[bidId]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	if b.db.decisions[bidId] == nil {
+		b.db.decisions[bidId] = map[string]string{}
+	}
+	b.db.decisions[bidId][userId] = decision
+	return nil
+}
+
+func (b *BidStore) GetDecisionsCount(ctx context.Context, bidId string) (int64, int64, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	var approved, rejected int64
+	for _, decision := range b.db.decisions[bidId] {
+		switch decision {
+		case "Approved":
+			approved++
+		case "Rejected":
+			rejected++
+		}
+	}
+	return approved, rejected, nil
+}
+
+func (b *BidStore) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	b.db.mu.RLock()
+	defer b.db.mu.RUnlock()
+
+	result := map[string]int64{}
+	for _, stored := range b.db.bids {
+		result[stored.latest().Status]++
+	}
+	return result, nil
+}
+
+// latest returns copy of the latest bid version
+func (b *storedBid) latest() models.Bid {
+	latest := b.versions[0]
+	for _, v := range b.versions[1:] {
+		if v.Version > latest.Version {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// pageBids sorts bids in given order and cuts requested page
+func pageBids(bids []*models.Bid, limit, offset int64, order string) []*models.Bid {
+	sort.SliceStable(bids, func(i, j int) bool {
+		x, y := bids[i], bids[j]
+		switch order {
+		case store.BidsByPrice:
+			if x.Currency != y.Currency {
+				// bids without price go last
+				return y.Currency == "" || (x.Currency != "" && x.Currency < y.Currency)
+			}
+			if c := compareNullLast(x.Amount, y.Amount); c != 0 {
+				return c < 0
+			}
+		case store.BidsByDelivery:
+			if c := compareNullLast(x.DeliveryDays, y.DeliveryDays); c != 0 {
+				return c < 0
+			}
+		}
+		return x.Name < y.Name
+	})
+	start, end := paginate(len(bids), limit, offset)
+	return bids[start:end]
+}
+
+// compareNullLast compares optional values placing missing ones after present like NULLS LAST does
+func compareNullLast[T int64 | float64](x, y *T) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return 1
+	case y == nil:
+		return -1
+	case *x < *y:
+		return -1
+	case *x > *y:
+		return 1
+	}
+	return 0
+}
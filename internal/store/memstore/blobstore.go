@@ -0,0 +1,53 @@
+package memstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// BlobStore keeps blobs in memory, like blobs on disk they live outside of DB and its units of work
+type BlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewBlobStore() *BlobStore {
+	return &BlobStore{
+		blobs: map[string][]byte{},
+	}
+}
+
+func (b *BlobStore) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", 0, fmt.Errorf("write blob: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.blobs[digest]; !ok {
+		b.blobs[digest] = content
+	}
+	return digest, int64(len(content)), nil
+}
+
+func (b *BlobStore) Open(ctx context.Context, digest string) (io.ReadCloser, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	content, ok := b.blobs[digest]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
@@ -0,0 +1,260 @@
+package memstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type responsible struct {
+	id     string
+	orgId  string
+	userId string
+	role   string
+}
+
+// defaultRole is given to responsibles added without role, same as column default of postgres
+const defaultRole = "admin"
+
+type storedTender struct {
+	orgId    string
+	username string
+	versions []models.Tender
+}
+
+type storedBid struct {
+	tenderId   string
+	authorType string
+	orgId      string
+	userId     string
+	versions   []models.Bid
+}
+
+type feedback struct {
+	id      string
+	bidId   string
+	userId  string
+	text    string
+	created time.Time
+}
+
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// noLock is used by transaction copy of DB which is already guarded by the lock of origin
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+// DB is in-memory replacement of the postgres database shared by all memstore stores
+type DB struct {
+	mu rwLocker
+
+	organizations map[string]*models.Organization
+	employees     map[string]*models.Employee
+	responsibles  []*responsible
+
+	tenders   map[string]*storedTender
+	bids      map[string]*storedBid
+	feedbacks []*feedback
+	// decisions maps bid id to decisions of every user
+	decisions map[string]map[string]string
+
+	audit []*models.AuditEntry
+
+	// criteria maps tender id to its criteria in order they were set
+	criteria map[string][]*models.Criterion
+	scores   []*models.Score
+
+	attachments []*models.Attachment
+}
+
+func NewDB() *DB {
+	return &DB{
+		mu:            &sync.RWMutex{},
+		organizations: map[string]*models.Organization{},
+		employees:     map[string]*models.Employee{},
+		tenders:       map[string]*storedTender{},
+		bids:          map[string]*storedBid{},
+		decisions:     map[string]map[string]string{},
+		criteria:      map[string][]*models.Criterion{},
+	}
+}
+
+// AddOrganization registers organization, empty id is generated
+func (d *DB) AddOrganization(id, name string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if id == "" {
+		id = uuid.New().String()
+	}
+	d.organizations[id] = &models.Organization{Id: id, Name: name, Created: time.Now().UTC()}
+	return id
+}
+
+// AddEmployee registers employee, empty id is generated
+func (d *DB) AddEmployee(emp models.Employee) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if emp.Id == "" {
+		emp.Id = uuid.New().String()
+	}
+	d.employees[emp.Id] = &emp
+	return emp.Id
+}
+
+// AddResponsible makes employee responsible for organization, empty role is defaultRole
+func (d *DB) AddResponsible(orgId, userId, role string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if role == "" {
+		role = defaultRole
+	}
+	id := uuid.New().String()
+	d.responsibles = append(d.responsibles, &responsible{id: id, orgId: orgId, userId: userId, role: role})
+	return id
+}
+
+type seed struct {
+	Organizations []struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"organizations"`
+	Employees    []models.Employee `json:"employees"`
+	Responsibles []struct {
+		OrgId  string `json:"organizationId"`
+		UserId string `json:"userId"`
+		Role   string `json:"role"`
+	} `json:"responsibles"`
+}
+
+// LoadSeed fills db with organizations, employees and responsibles from json file
+func (d *DB) LoadSeed(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read seed: %w", err)
+	}
+
+	var sd seed
+	err = json.Unmarshal(data, &sd)
+	if err != nil {
+		return fmt.Errorf("parse seed: %w", err)
+	}
+
+	for _, org := range sd.Organizations {
+		d.AddOrganization(org.Id, org.Name)
+	}
+	for _, emp := range sd.Employees {
+		d.AddEmployee(emp)
+	}
+	for _, resp := range sd.Responsibles {
+		d.AddResponsible(resp.OrgId, resp.UserId, resp.Role)
+	}
+	return nil
+}
+
+func (d *DB) employeeByUsername(username string) (*models.Employee, bool) {
+	for _, emp := range d.employees {
+		if emp.Username == username {
+			return emp, true
+		}
+	}
+	return nil, false
+}
+
+// clone returns deep copy of db contents guarded by no lock
+func (d *DB) clone() *DB {
+	c := &DB{
+		mu:            noLock{},
+		organizations: make(map[string]*models.Organization, len(d.organizations)),
+		employees:     make(map[string]*models.Employee, len(d.employees)),
+		responsibles:  make([]*responsible, 0, len(d.responsibles)),
+		tenders:       make(map[string]*storedTender, len(d.tenders)),
+		bids:          make(map[string]*storedBid, len(d.bids)),
+		feedbacks:     make([]*feedback, 0, len(d.feedbacks)),
+		decisions:     make(map[string]map[string]string, len(d.decisions)),
+		audit:         make([]*models.AuditEntry, 0, len(d.audit)),
+		criteria:      make(map[string][]*models.Criterion, len(d.criteria)),
+		scores:        make([]*models.Score, 0, len(d.scores)),
+		attachments:   make([]*models.Attachment, 0, len(d.attachments)),
+	}
+	for id, org := range d.organizations {
+		o := *org
+		c.organizations[id] = &o
+	}
+	for id, emp := range d.employees {
+		e := *emp
+		c.employees[id] = &e
+	}
+	for _, resp := range d.responsibles {
+		r := *resp
+		c.responsibles = append(c.responsibles, &r)
+	}
+	for id, tnd := range d.tenders {
+		t := *tnd
+		t.versions = append([]models.Tender(nil), tnd.versions...)
+		c.tenders[id] = &t
+	}
+	for id, bid := range d.bids {
+		b := *bid
+		b.versions = append([]models.Bid(nil), bid.versions...)
+		c.bids[id] = &b
+	}
+	for _, feed := range d.feedbacks {
+		f := *feed
+		c.feedbacks = append(c.feedbacks, &f)
+	}
+	for bidId, decisions := range d.decisions {
+		c.decisions[bidId] = make(map[string]string, len(decisions))
+		for userId, decision := range decisions {
+			c.decisions[bidId][userId] = decision
+		}
+	}
+	for _, entry := range d.audit {
+		e := *entry
+		c.audit = append(c.audit, &e)
+	}
+	for tenderId, criteria := range d.criteria {
+		c.criteria[tenderId] = make([]*models.Criterion, 0, len(criteria))
+		for _, criterion := range criteria {
+			cr := *criterion
+			c.criteria[tenderId] = append(c.criteria[tenderId], &cr)
+		}
+	}
+	for _, score := range d.scores {
+		s := *score
+		c.scores = append(c.scores, &s)
+	}
+	for _, att := range d.attachments {
+		a := *att
+		c.attachments = append(c.attachments, &a)
+	}
+	return c
+}
+
+// paginate returns [offset, offset+limit) bounds for slice of given length
+func paginate(length int, limit, offset int64) (int, int) {
+	if offset >= int64(length) {
+		return length, length
+	}
+	end := offset + limit
+	if end > int64(length) {
+		end = int64(length)
+	}
+	return int(offset), int(end)
+}
@@ -0,0 +1,112 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/google/uuid"
+)
+
+type EmployeeStore struct {
+	db *DB
+}
+
+func NewEmployeeStore(db *DB) *EmployeeStore {
+	return &EmployeeStore{
+		db: db,
+	}
+}
+
+func (e *EmployeeStore) Create(ctx context.Context, emp *models.Employee) (*models.Employee, error) {
+	e.db.mu.Lock()
+	defer e.db.mu.Unlock()
+
+	if _, ok := e.db.employeeByUsername(emp.Username); ok {
+		return nil, store.ErrRecordAlreadyExists
+	}
+
+	emp.Id = uuid.New().String()
+	stored := *emp
+	e.db.employees[emp.Id] = &stored
+	return emp, nil
+}
+
+func (e *EmployeeStore) Get(ctx context.Context, userId string) (*models.Employee, error) {
+	e.db.mu.RLock()
+	defer e.db.mu.RUnlock()
+
+	emp, ok := e.db.employees[userId]
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+	result := *emp
+	return &result, nil
+}
+
+func (e *EmployeeStore) GetList(ctx context.Context, limit, offset int64) ([]*models.Employee, error) {
+	e.db.mu.RLock()
+	defer e.db.mu.RUnlock()
+
+	emps := make([]*models.Employee, 0, len(e.db.employees))
+	for _, emp := range e.db.employees {
+		stored := *emp
+		emps = append(emps, &stored)
+	}
+	slices.SortFunc(emps, func(a, b *models.Employee) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	start, end := paginate(len(emps), limit, offset)
+	return emps[start:end], nil
+}
+
+func (e *EmployeeStore) Update(ctx context.Context, emp *models.Employee) (*models.Employee, error) {
+	e.db.mu.Lock()
+	defer e.db.mu.Unlock()
+
+	stored, ok := e.db.employees[emp.Id]
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+	stored.FirstName = emp.FirstName
+	stored.LastName = emp.LastName
+
+	result := *stored
+	return &result, nil
+}
+
+func (e *EmployeeStore) Delete(ctx context.Context, userId string) error {
+	e.db.mu.Lock()
+	defer e.db.mu.Unlock()
+
+	emp, ok := e.db.employees[userId]
+	if !ok {
+		return store.ErrUserNotFound
+	}
+
+	for _, tnd := range e.db.tenders {
+		if tnd.username == emp.Username {
+			return store.ErrRecordInUse
+		}
+	}
+	for _, bid := range e.db.bids {
+		if bid.authorType == "User" && bid.userId == userId {
+			return store.ErrRecordInUse
+		}
+	}
+	for _, entry := range e.db.audit {
+		if entry.ActorId == userId {
+			return store.ErrRecordInUse
+		}
+	}
+
+	// responsibilities go with employee like ON DELETE CASCADE does
+	e.db.responsibles = slices.DeleteFunc(e.db.responsibles, func(r *responsible) bool {
+		return r.userId == userId
+	})
+	delete(e.db.employees, userId)
+	return nil
+}
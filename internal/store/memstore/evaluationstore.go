@@ -0,0 +1,89 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type EvaluationStore struct {
+	db *DB
+}
+
+func NewEvaluationStore(db *DB) *EvaluationStore {
+	return &EvaluationStore{
+		db: db,
+	}
+}
+
+func (e *EvaluationStore) SetCriteria(ctx context.Context, tenderId string, criteria []*models.Criterion) ([]*models.Criterion, error) {
+	e.db.mu.Lock()
+	defer e.db.mu.Unlock()
+
+	// scores given by replaced criteria are dropped like ON DELETE CASCADE does
+	old := make([]string, 0, len(e.db.criteria[tenderId]))
+	for _, c := range e.db.criteria[tenderId] {
+		old = append(old, c.Id)
+	}
+	e.db.scores = slices.DeleteFunc(e.db.scores, func(s *models.Score) bool {
+		return slices.Contains(old, s.CriterionId)
+	})
+
+	stored := make([]*models.Criterion, 0, len(criteria))
+	for _, c := range criteria {
+		c.Id = uuid.New().String()
+		c.TenderId = tenderId
+		criterion := *c
+		stored = append(stored, &criterion)
+	}
+	e.db.criteria[tenderId] = stored
+	return criteria, nil
+}
+
+func (e *EvaluationStore) GetCriteria(ctx context.Context, tenderId string) ([]*models.Criterion, error) {
+	e.db.mu.RLock()
+	defer e.db.mu.RUnlock()
+
+	result := make([]*models.Criterion, 0, len(e.db.criteria[tenderId]))
+	for _, c := range e.db.criteria[tenderId] {
+		criterion := *c
+		result = append(result, &criterion)
+	}
+	return result, nil
+}
+
+func (e *EvaluationStore) SetScore(ctx context.Context, score *models.Score) error {
+	e.db.mu.Lock()
+	defer e.db.mu.Unlock()
+
+	score.Created = time.Now().UTC()
+	stored := *score
+	for i, s := range e.db.scores {
+		if s.BidId == score.BidId && s.CriterionId == score.CriterionId && s.EvaluatorId == score.EvaluatorId {
+			e.db.scores[i] = &stored
+			return nil
+		}
+	}
+	e.db.scores = append(e.db.scores, &stored)
+	return nil
+}
+
+func (e *EvaluationStore) GetScores(ctx context.Context, tenderId string) ([]*models.Score, error) {
+	e.db.mu.RLock()
+	defer e.db.mu.RUnlock()
+
+	result := []*models.Score{}
+	for _, s := range e.db.scores {
+		for _, c := range e.db.criteria[tenderId] {
+			if c.Id == s.CriterionId {
+				score := *s
+				result = append(result, &score)
+				break
+			}
+		}
+	}
+	return result, nil
+}
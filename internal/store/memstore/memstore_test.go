@@ -0,0 +1,11 @@
+package memstore_test
+
+import (
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+)
+
+func TestMemstore(t *testing.T) {
+	storetest.Run(t, storetest.NewMemory)
+}
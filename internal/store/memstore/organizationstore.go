@@ -0,0 +1,176 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/google/uuid"
+)
+
+type OrganizationStore struct {
+	db *DB
+}
+
+func NewOrganizationStore(db *DB) *OrganizationStore {
+	return &OrganizationStore{
+		db: db,
+	}
+}
+
+func (o *OrganizationStore) Create(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	org.Id = uuid.New().String()
+	org.Created = time.Now().UTC()
+
+	stored := *org
+	o.db.organizations[org.Id] = &stored
+	return org, nil
+}
+
+func (o *OrganizationStore) Get(ctx context.Context, orgId string) (*models.Organization, error) {
+	o.db.mu.RLock()
+	defer o.db.mu.RUnlock()
+
+	org, ok := o.db.organizations[orgId]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	result := *org
+	return &result, nil
+}
+
+func (o *OrganizationStore) GetList(ctx context.Context, limit, offset int64) ([]*models.Organization, error) {
+	o.db.mu.RLock()
+	defer o.db.mu.RUnlock()
+
+	orgs := make([]*models.Organization, 0, len(o.db.organizations))
+	for _, org := range o.db.organizations {
+		stored := *org
+		orgs = append(orgs, &stored)
+	}
+	slices.SortFunc(orgs, func(a, b *models.Organization) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	start, end := paginate(len(orgs), limit, offset)
+	return orgs[start:end], nil
+}
+
+func (o *OrganizationStore) Update(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	stored, ok := o.db.organizations[org.Id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	stored.Name = org.Name
+	stored.Description = org.Description
+	stored.Type = org.Type
+
+	result := *stored
+	return &result, nil
+}
+
+func (o *OrganizationStore) Delete(ctx context.Context, orgId string) error {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	if _, ok := o.db.organizations[orgId]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	for _, tnd := range o.db.tenders {
+		if tnd.orgId == orgId {
+			return store.ErrRecordInUse
+		}
+	}
+	for _, bid := range o.db.bids {
+		if bid.orgId == orgId {
+			return store.ErrRecordInUse
+		}
+	}
+	for _, entry := range o.db.audit {
+		if entry.OrgId == orgId {
+			return store.ErrRecordInUse
+		}
+	}
+
+	// responsibilities go with organization like ON DELETE CASCADE does
+	o.db.responsibles = slices.DeleteFunc(o.db.responsibles, func(r *responsible) bool {
+		return r.orgId == orgId
+	})
+	delete(o.db.organizations, orgId)
+	return nil
+}
+
+func (o *OrganizationStore) Grant(ctx context.Context, orgId, userId, role string) error {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	for _, resp := range o.db.responsibles {
+		if resp.orgId == orgId && resp.userId == userId {
+			return store.ErrRecordAlreadyExists
+		}
+	}
+	o.db.responsibles = append(o.db.responsibles, &responsible{
+		id:     uuid.New().String(),
+		orgId:  orgId,
+		userId: userId,
+		role:   role,
+	})
+	return nil
+}
+
+func (o *OrganizationStore) SetRole(ctx context.Context, orgId, userId, role string) error {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	for _, resp := range o.db.responsibles {
+		if resp.orgId == orgId && resp.userId == userId {
+			resp.role = role
+			return nil
+		}
+	}
+	return store.ErrRecordNotFound
+}
+
+func (o *OrganizationStore) Revoke(ctx context.Context, orgId, userId string) error {
+	o.db.mu.Lock()
+	defer o.db.mu.Unlock()
+
+	count := len(o.db.responsibles)
+	o.db.responsibles = slices.DeleteFunc(o.db.responsibles, func(r *responsible) bool {
+		return r.orgId == orgId && r.userId == userId
+	})
+	if len(o.db.responsibles) == count {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (o *OrganizationStore) GetResponsibles(ctx context.Context, orgId string) ([]*models.Member, error) {
+	o.db.mu.RLock()
+	defer o.db.mu.RUnlock()
+
+	result := []*models.Member{}
+	for _, resp := range o.db.responsibles {
+		if resp.orgId != orgId {
+			continue
+		}
+		if emp, ok := o.db.employees[resp.userId]; ok {
+			stored := *emp
+			result = append(result, &models.Member{Employee: &stored, Role: resp.role})
+		}
+	}
+	slices.SortFunc(result, func(a, b *models.Member) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	return result, nil
+}
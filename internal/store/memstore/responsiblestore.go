@@ -0,0 +1,148 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+type ResponsibleStore struct {
+	db *DB
+}
+
+func NewResponsibleStore(db *DB) *ResponsibleStore {
+	return &ResponsibleStore{
+		db: db,
+	}
+}
+
+func (r *ResponsibleStore) GetResponsibleUUID(ctx context.Context, responsible *models.Responsible) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emp, ok := r.db.employeeByUsername(responsible.Username)
+	if !ok {
+		return "", store.ErrUserNotFound
+	}
+
+	for _, resp := range r.db.responsibles {
+		if resp.userId == emp.Id && resp.orgId == responsible.OrgId {
+			return resp.id, nil
+		}
+	}
+	return "", store.ErrRecordNotFound
+}
+
+func (r *ResponsibleStore) GetRespUUIDs(ctx context.Context, username string) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emp, ok := r.db.employeeByUsername(username)
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+
+	var result []string
+	for _, resp := range r.db.responsibles {
+		if resp.userId == emp.Id {
+			result = append(result, resp.id)
+		}
+	}
+	return result, nil
+}
+
+func (r *ResponsibleStore) IsResponcible(ctx context.Context, responsible *models.Responsible) error {
+	_, err := r.GetResponsibleUUID(ctx, responsible)
+	return err
+}
+
+func (r *ResponsibleStore) IsUserExists(ctx context.Context, username string) error {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.employeeByUsername(username); !ok {
+		return store.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *ResponsibleStore) GetOrgIds(ctx context.Context, username string) ([]string, error) {
+	userId, err := r.GetUserId(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ResponcibleForOrgs(ctx, userId)
+}
+
+func (r *ResponsibleStore) ResponcibleForOrgs(ctx context.Context, userId string) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var orgIds []string
+	for _, resp := range r.db.responsibles {
+		if resp.userId == userId {
+			orgIds = append(orgIds, resp.orgId)
+		}
+	}
+	if len(orgIds) == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+	sort.Strings(orgIds)
+	return orgIds, nil
+}
+
+func (r *ResponsibleStore) GetUserId(ctx context.Context, username string) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emp, ok := r.db.employeeByUsername(username)
+	if !ok {
+		return "", store.ErrUserNotFound
+	}
+	return emp.Id, nil
+}
+
+func (r *ResponsibleStore) GetRoles(ctx context.Context, userId string) (map[string]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	roles := map[string]string{}
+	for _, resp := range r.db.responsibles {
+		if resp.userId == userId {
+			roles[resp.orgId] = resp.role
+		}
+	}
+	if len(roles) == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+	return roles, nil
+}
+
+func (r *ResponsibleStore) CountResponsibles(ctx context.Context, orgId string, roles []string) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int64
+	for _, resp := range r.db.responsibles {
+		if resp.orgId == orgId && slices.Contains(roles, resp.role) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ResponsibleStore) GetEmployee(ctx context.Context, userId string) (*models.Employee, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	emp, ok := r.db.employees[userId]
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+	result := *emp
+	return &result, nil
+}
@@ -0,0 +1,254 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/google/uuid"
+)
+
+type TenderStore struct {
+	db *DB
+}
+
+func NewTenderStore(db *DB) *TenderStore {
+	return &TenderStore{
+		db: db,
+	}
+}
+
+func (t *TenderStore) GetLimitedList(ctx context.Context, limit, offset int64, servType []string) ([]*models.Tender, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	result := []*models.Tender{}
+	for _, tnd := range t.db.tenders {
+		latest := tnd.latest()
+		if len(servType) != 0 && !slices.Contains(servType, latest.ServType) {
+			continue
+		}
+		latest.OrgId = ""
+		result = append(result, &latest)
+	}
+	return pageTenders(result, limit, offset), nil
+}
+
+func (t *TenderStore) Create(ctx context.Context, tnd *models.Tender, resp *models.Responsible) (*models.Tender, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	tnd.Id = uuid.New().String()
+	tnd.Status = "Created"
+	tnd.Version = 1
+	tnd.Created = time.Now().UTC()
+
+	version := *tnd
+	version.OrgId = resp.OrgId
+	t.db.tenders[tnd.Id] = &storedTender{
+		orgId:    resp.OrgId,
+		username: resp.Username,
+		versions: []models.Tender{version},
+	}
+
+	return tnd, nil
+}
+
+func (t *TenderStore) GetUserTenders(ctx context.Context, limit, offset int64, username string) ([]*models.Tender, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	result := []*models.Tender{}
+	for _, tnd := range t.db.tenders {
+		if tnd.username != username {
+			continue
+		}
+		latest := tnd.latest()
+		latest.OrgId = ""
+		result = append(result, &latest)
+	}
+	return pageTenders(result, limit, offset), nil
+}
+
+func (t *TenderStore) GetStatus(ctx context.Context, tenderId string) (string, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	tnd, ok := t.db.tenders[tenderId]
+	if !ok {
+		return "", store.ErrRecordNotFound
+	}
+	return tnd.latest().Status, nil
+}
+
+func (t *TenderStore) GetTenderLatestVersion(ctx context.Context, tenderId string) (int64, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	tnd, ok := t.db.tenders[tenderId]
+	if !ok {
+		return -1, store.ErrRecordNotFound
+	}
+	return tnd.latest().Version, nil
+}
+
+func (t *TenderStore) GetCondition(ctx context.Context, tenderId string, version int64) (*models.Tender, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	tnd, ok := t.db.tenders[tenderId]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	if version == store.Latest {
+		latest := tnd.latest()
+		return &latest, nil
+	}
+
+	for _, v := range tnd.versions {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (t *TenderStore) GetVersionsList(ctx context.Context, tenderId string, limit, offset int64) ([]*models.Tender, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	tnd, ok := t.db.tenders[tenderId]
+	if !ok {
+		return []*models.Tender{}, nil
+	}
+
+	versions := make([]*models.Tender, 0, len(tnd.versions))
+	for _, v := range tnd.versions {
+		version := v
+		versions = append(versions, &version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	from, to := paginate(len(versions), limit, offset)
+	return versions[from:to], nil
+}
+
+func (t *TenderStore) UpdateCondition(ctx context.Context, newCondition *models.Tender) (*models.Tender, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	tnd, ok := t.db.tenders[newCondition.Id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	for _, v := range tnd.versions {
+		if v.Version == newCondition.Version {
+			return nil, store.ErrRecordAlreadyExists
+		}
+	}
+
+	version := *newCondition
+	version.OrgId = tnd.orgId
+	tnd.versions = append(tnd.versions, version)
+
+	return newCondition, nil
+}
+
+func (t *TenderStore) GetExpired(ctx context.Context, now time.Time, limit int64) ([]*models.Tender, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	result := []*models.Tender{}
+	for _, tnd := range t.db.tenders {
+		latest := tnd.latest()
+		if latest.Status != "Published" || !latest.Expired(now) {
+			continue
+		}
+		result = append(result, &latest)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Deadline.Before(*result[j].Deadline)
+	})
+
+	_, end := paginate(len(result), limit, 0)
+	return result[:end], nil
+}
+
+// TryLock only checks tender exists, units of work of memstore don't run concurrently
+func (t *TenderStore) TryLock(ctx context.Context, tenderId string) (bool, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	_, ok := t.db.tenders[tenderId]
+	return ok, nil
+}
+
+func (t *TenderStore) IsResponcibleFor(ctx context.Context, tenderId string, respUUIDs []string) error {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	tnd, ok := t.db.tenders[tenderId]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	for _, resp := range t.db.responsibles {
+		if resp.orgId == tnd.orgId && slices.Contains(respUUIDs, resp.id) {
+			return nil
+		}
+	}
+	return store.ErrRecordNotFound
+}
+
+func (t *TenderStore) GetOrgIdByBidId(ctx context.Context, bidId string) (string, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	b, ok := t.db.bids[bidId]
+	if !ok {
+		return "", store.ErrRecordNotFound
+	}
+	tnd, ok := t.db.tenders[b.tenderId]
+	if !ok {
+		return "", store.ErrRecordNotFound
+	}
+	return tnd.orgId, nil
+}
+
+func (t *TenderStore) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	result := map[string]int64{}
+	for _, stored := range t.db.tenders {
+		result[stored.latest().Status]++
+	}
+	return result, nil
+}
+
+// latest returns copy of the latest tender version
+func (t *storedTender) latest() models.Tender {
+	latest := t.versions[0]
+	for _, v := range t.versions[1:] {
+		if v.Version > latest.Version {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// pageTenders sorts tenders by name and cuts requested page
+func pageTenders(tenders []*models.Tender, limit, offset int64) []*models.Tender {
+	sort.SliceStable(tenders, func(i, j int) bool {
+		return tenders[i].Name < tenders[j].Name
+	})
+	start, end := paginate(len(tenders), limit, offset)
+	return tenders[start:end]
+}
@@ -0,0 +1,49 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// UnitOfWork runs memstore stores over copy of DB and applies it only when whole work succeeded
+type UnitOfWork struct {
+	db *DB
+}
+
+func NewUnitOfWork(db *DB) *UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos store.Repositories) error) error {
+	u.db.mu.Lock()
+	defer u.db.mu.Unlock()
+
+	tx := u.db.clone()
+	err := fn(store.Repositories{
+		Tenders:      NewTenderStore(tx),
+		Bids:         NewBidStore(tx),
+		Responsibles: NewResponsibleStore(tx),
+		Audit:        NewAuditStore(tx),
+		Evaluations:  NewEvaluationStore(tx),
+		Attachments:  NewAttachmentStore(tx),
+	})
+	if err != nil {
+		return err
+	}
+
+	u.db.organizations = tx.organizations
+	u.db.employees = tx.employees
+	u.db.responsibles = tx.responsibles
+	u.db.tenders = tx.tenders
+	u.db.bids = tx.bids
+	u.db.feedbacks = tx.feedbacks
+	u.db.decisions = tx.decisions
+	u.db.audit = tx.audit
+	u.db.criteria = tx.criteria
+	u.db.scores = tx.scores
+	u.db.attachments = tx.attachments
+	return nil
+}
@@ -0,0 +1,265 @@
+package organizationstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+)
+
+type OrganizationStore struct {
+	db       store.Querier
+	timeouts store.Timeouts
+}
+
+func New(db store.Querier, timeouts store.Timeouts) *OrganizationStore {
+	return &OrganizationStore{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+func (o *OrganizationStore) Create(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := o.db.QueryRowContext(ctx,
+		"INSERT INTO organization (name, description, type) VALUES ($1, $2, $3) RETURNING id, created_at;",
+		org.Name,
+		org.Description,
+		nullType(org.Type),
+	).Scan(&org.Id, &org.Created)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	return org, nil
+}
+
+func (o *OrganizationStore) Get(ctx context.Context, orgId string) (*models.Organization, error) {
+	ctx, cancel := o.timeouts.ForRead(ctx)
+	defer cancel()
+
+	org, err := scanOrganization(o.db.QueryRowContext(ctx,
+		"SELECT id, name, description, type, created_at FROM organization WHERE id = $1;",
+		orgId,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, pgerr.Translate(err)
+	}
+	return org, nil
+}
+
+func (o *OrganizationStore) GetList(ctx context.Context, limit, offset int64) ([]*models.Organization, error) {
+	ctx, cancel := o.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx,
+		"SELECT id, name, description, type, created_at "+
+			"FROM organization "+
+			"ORDER BY name ASC "+
+			"LIMIT $1 "+
+			"OFFSET $2;",
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result = append(result, org)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+func (o *OrganizationStore) Update(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	res, err := o.db.ExecContext(ctx,
+		"UPDATE organization SET name = $2, description = $3, type = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $1;",
+		org.Id,
+		org.Name,
+		org.Description,
+		nullType(org.Type),
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+	return o.Get(ctx, org.Id)
+}
+
+func (o *OrganizationStore) Delete(ctx context.Context, orgId string) error {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	_, err := o.Get(ctx, orgId)
+	if err != nil {
+		return err
+	}
+
+	// tables of tenders and bids cascade deletes of organization, so organization
+	// with any history is kept
+	res, err := o.db.ExecContext(ctx,
+		"DELETE FROM organization AS o WHERE o.id = $1 "+
+			"AND NOT EXISTS (SELECT 1 FROM tenders WHERE organization_id = o.id) "+
+			"AND NOT EXISTS (SELECT 1 FROM bids WHERE organization_id = o.id) "+
+			"AND NOT EXISTS (SELECT 1 FROM audit_log WHERE organization_id = o.id);",
+		orgId,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordInUse
+	}
+	return nil
+}
+
+func (o *OrganizationStore) Grant(ctx context.Context, orgId, userId, role string) error {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	// organization_responsible has no unique constraint, so duplicates are filtered by the insert itself
+	res, err := o.db.ExecContext(ctx,
+		"INSERT INTO organization_responsible (organization_id, user_id, role) "+
+			"SELECT $1, $2, $3 WHERE NOT EXISTS ("+
+			"SELECT 1 FROM organization_responsible WHERE organization_id = $1 AND user_id = $2"+
+			");",
+		orgId,
+		userId,
+		role,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordAlreadyExists
+	}
+	return nil
+}
+
+func (o *OrganizationStore) SetRole(ctx context.Context, orgId, userId, role string) error {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	res, err := o.db.ExecContext(ctx,
+		"UPDATE organization_responsible SET role = $3 WHERE organization_id = $1 AND user_id = $2;",
+		orgId,
+		userId,
+		role,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (o *OrganizationStore) Revoke(ctx context.Context, orgId, userId string) error {
+	ctx, cancel := o.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	res, err := o.db.ExecContext(ctx,
+		"DELETE FROM organization_responsible WHERE organization_id = $1 AND user_id = $2;",
+		orgId,
+		userId,
+	)
+	if err != nil {
+		return pgerr.Translate(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (o *OrganizationStore) GetResponsibles(ctx context.Context, orgId string) ([]*models.Member, error) {
+	ctx, cancel := o.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx,
+		"SELECT e.id, e.username, e.first_name, e.last_name, r.role "+
+			"FROM organization_responsible AS r "+
+			"INNER JOIN employee AS e ON e.id = r.user_id "+
+			"WHERE r.organization_id = $1 "+
+			"ORDER BY e.username ASC;",
+		orgId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Member{}
+	for rows.Next() {
+		member := models.Member{Employee: &models.Employee{}}
+		var firstName, lastName sql.NullString
+		err = rows.Scan(&member.Id, &member.Username, &firstName, &lastName, &member.Role)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		member.FirstName = firstName.String
+		member.LastName = lastName.String
+		result = append(result, &member)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrganization(row scanner) (*models.Organization, error) {
+	var org models.Organization
+	var description, orgType sql.NullString
+	err := row.Scan(&org.Id, &org.Name, &description, &orgType, &org.Created)
+	if err != nil {
+		return nil, err
+	}
+	org.Description = description.String
+	org.Type = orgType.String
+	return &org, nil
+}
+
+// nullType stores organization without legal form as NULL, empty string isn't valid organization_type
+func nullType(orgType string) sql.NullString {
+	return sql.NullString{String: orgType, Valid: orgType != ""}
+}
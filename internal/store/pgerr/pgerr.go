@@ -0,0 +1,89 @@
+// Package pgerr classifies errors of postgres driver into errors of store package
+package pgerr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	tooManyConnections   = "53300"
+	queryCanceled        = "57014"
+	adminShutdown        = "57P01"
+	crashShutdown        = "57P02"
+	cannotConnectNow     = "57P03"
+)
+
+// connectionException is class of codes of lost or refused connection
+const connectionException = "08"
+
+// Translate wraps err into store error it belongs to, original error stays in chain
+// so it can be logged and inspected. Unknown errors, sql.ErrNoRows and context.Canceled
+// are returned as is, cancellation is caller's decision and isn't store failure.
+func Translate(err error) error {
+	kind := classify(err)
+	if kind == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+func classify(err error) error {
+	// context errors look like net timeouts, but they are caused by caller, not connection
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return store.ErrTimeout
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return store.ErrRecordAlreadyExists
+		case foreignKeyViolation:
+			return store.ErrReferenceNotFound
+		case serializationFailure, deadlockDetected:
+			return store.ErrRetryable
+		case queryCanceled:
+			// statement_timeout, query cancelled with its context is reported so too,
+			// callers tell cancellation apart by their context
+			return store.ErrTimeout
+		case tooManyConnections, adminShutdown, crashShutdown, cannotConnectNow:
+			return store.ErrConnClosed
+		}
+		if pqErr.Code.Class() == connectionException {
+			return store.ErrConnClosed
+		}
+		return nil
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EPIPE):
+		return store.ErrConnClosed
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package pgerr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/lib/pq"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"unique violation", &pq.Error{Code: uniqueViolation}, store.ErrRecordAlreadyExists},
+		{"foreign key violation", &pq.Error{Code: foreignKeyViolation}, store.ErrReferenceNotFound},
+		{"serialization failure", &pq.Error{Code: serializationFailure}, store.ErrRetryable},
+		{"deadlock", fmt.Errorf("update: %w", &pq.Error{Code: deadlockDetected}), store.ErrRetryable},
+		{"admin shutdown", &pq.Error{Code: adminShutdown}, store.ErrConnClosed},
+		{"connection exception", &pq.Error{Code: "08006"}, store.ErrConnClosed},
+		{"bad connection", driver.ErrBadConn, store.ErrConnClosed},
+		{"connection done", sql.ErrConnDone, store.ErrConnClosed},
+		{"unexpected eof", io.ErrUnexpectedEOF, store.ErrConnClosed},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, store.ErrConnClosed},
+		{"connection reset", syscall.ECONNRESET, store.ErrConnClosed},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), store.ErrTimeout},
+		{"statement timeout", &pq.Error{Code: queryCanceled}, store.ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Translate(tt.err)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("expected %q, got %v", tt.kind, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected original error kept in chain, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	for _, err := range []error{
+		nil,
+		sql.ErrNoRows,
+		context.Canceled,
+		&pq.Error{Code: "42P01"},
+		errors.New("unknown"),
+	} {
+		got := Translate(err)
+		if got != err {
+			t.Fatalf("expected %v returned as is, got %v", err, got)
+		}
+	}
+}
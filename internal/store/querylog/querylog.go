@@ -0,0 +1,77 @@
+// Package querylog logs failed and slow queries of sql stores together with
+// identifier of request they were made for
+package querylog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/reqctx"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+type Querier struct {
+	q      store.Querier
+	slow   time.Duration
+	logger *logrus.Entry
+}
+
+// New wraps querier, queries lasting longer than slow are logged as warnings, zero slow disables it
+func New(q store.Querier, slow time.Duration, log *logrus.Logger) *Querier {
+	logger := log.WithFields(logrus.Fields{
+		"component": "store",
+	})
+
+	return &Querier{
+		q:      q,
+		slow:   slow,
+		logger: logger,
+	}
+}
+
+func (q *Querier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := q.q.ExecContext(ctx, query, args...)
+	q.log(ctx, query, start, err)
+	return result, err
+}
+
+func (q *Querier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := q.q.QueryContext(ctx, query, args...)
+	q.log(ctx, query, start, err)
+	return rows, err
+}
+
+func (q *Querier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := q.q.QueryRowContext(ctx, query, args...)
+	q.log(ctx, query, start, row.Err())
+	return row
+}
+
+func (q *Querier) log(ctx context.Context, query string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	logger := q.logger.WithFields(logrus.Fields{
+		"request_id": reqctx.RequestID(ctx),
+		"elapsed":    elapsed,
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+		logger.Warnf("query interrupted: %s, query: %s", err, compact(query))
+	case err != nil:
+		logger.Errorf("query failed: %s, query: %s", err, compact(query))
+	case q.slow > 0 && elapsed > q.slow:
+		logger.Warnf("slow query: %s", compact(query))
+	}
+}
+
+// compact joins query parts into single line
+func compact(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+type readOnlyKey struct{}
+
+// Replicated sends queries of reading operations to replica and all others to primary.
+// Replica may lag behind primary, so operations needing latest data run in unit of work.
+type Replicated struct {
+	primary Querier
+	replica Querier
+}
+
+func NewReplicated(primary, replica Querier) *Replicated {
+	return &Replicated{
+		primary: primary,
+		replica: replica,
+	}
+}
+
+func (r *Replicated) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return r.route(ctx).ExecContext(ctx, query, args...)
+}
+
+func (r *Replicated) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.route(ctx).QueryContext(ctx, query, args...)
+}
+
+func (r *Replicated) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return r.route(ctx).QueryRowContext(ctx, query, args...)
+}
+
+func (r *Replicated) route(ctx context.Context) Querier {
+	if readOnly, _ := ctx.Value(readOnlyKey{}).(bool); readOnly {
+		return r.replica
+	}
+	return r.primary
+}
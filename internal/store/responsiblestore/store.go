@@ -1,27 +1,35 @@
 package responsiblestore
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+	"github.com/lib/pq"
 )
 
 type ResponsibleStore struct {
-	db *sql.DB
+	db       store.Querier
+	timeouts store.Timeouts
 }
 
-func New(db *sql.DB) *ResponsibleStore {
+func New(db store.Querier, timeouts store.Timeouts) *ResponsibleStore {
 	return &ResponsibleStore{
-		db: db,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
-func (r *ResponsibleStore) GetResponsibleUUID(responsibles *models.Responsible) (string, error) {
+func (r *ResponsibleStore) GetResponsibleUUID(ctx context.Context, responsibles *models.Responsible) (string, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var userId string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id FROM employee WHERE username = $1;",
 		responsibles.Username,
 	).Scan(&userId)
@@ -29,16 +37,13 @@ func (r *ResponsibleStore) GetResponsibleUUID(responsibles *models.Responsible)
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", store.ErrUserNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return "", store.ErrConnClosed
-		}
-		return "", err
+		return "", pgerr.Translate(err)
 	}
 
 	fmt.Println("user found")
 
 	var resposiblesUUID string
-	err = r.db.QueryRow(
+	err = r.db.QueryRowContext(ctx,
 		"SELECT id FROM organization_responsible WHERE user_id = $1 AND organization_id = $2;",
 		userId,
 		responsibles.OrgId,
@@ -47,17 +52,17 @@ func (r *ResponsibleStore) GetResponsibleUUID(responsibles *models.Responsible)
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", store.ErrRecordNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return "", store.ErrConnClosed
-		}
-		return "", err
+		return "", pgerr.Translate(err)
 	}
 	return resposiblesUUID, nil
 }
 
-func (r *ResponsibleStore) GetRespUUIDs(username string) ([]string, error) {
+func (r *ResponsibleStore) GetRespUUIDs(ctx context.Context, username string) ([]string, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var userId string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id FROM employee WHERE username = $1;",
 		username,
 	).Scan(&userId)
@@ -65,13 +70,10 @@ func (r *ResponsibleStore) GetRespUUIDs(username string) ([]string, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrUserNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		"SELECT id FROM organization_responsible WHERE user_id = $1;",
 		userId,
 	)
@@ -79,28 +81,28 @@ func (r *ResponsibleStore) GetRespUUIDs(username string) ([]string, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
+	defer rows.Close()
 
 	var resposiblesUUIDs []string
 	for rows.Next() {
 		var uuid string
 		err = rows.Scan(&uuid)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		resposiblesUUIDs = append(resposiblesUUIDs, uuid)
 	}
-
-	return resposiblesUUIDs, nil
+	return resposiblesUUIDs, pgerr.Translate(rows.Err())
 }
 
-func (r *ResponsibleStore) IsResponcible(responsible *models.Responsible) error {
+func (r *ResponsibleStore) IsResponcible(ctx context.Context, responsible *models.Responsible) error {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var userId string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id FROM employee WHERE username = $1;",
 		responsible.Username,
 	).Scan(&userId)
@@ -108,14 +110,11 @@ func (r *ResponsibleStore) IsResponcible(responsible *models.Responsible) error
 		if errors.Is(err, sql.ErrNoRows) {
 			return store.ErrUserNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return store.ErrConnClosed
-		}
-		return err
+		return pgerr.Translate(err)
 	}
 
 	var respId string
-	err = r.db.QueryRow(
+	err = r.db.QueryRowContext(ctx,
 		"SELECT id FROM organization_responsible WHERE user_id = $1 AND organization_id = $2;",
 		userId,
 		responsible.OrgId,
@@ -124,17 +123,17 @@ func (r *ResponsibleStore) IsResponcible(responsible *models.Responsible) error
 		if errors.Is(err, sql.ErrNoRows) {
 			return store.ErrRecordNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return store.ErrConnClosed
-		}
-		return err
+		return pgerr.Translate(err)
 	}
 	return nil
 }
 
-func (r *ResponsibleStore) IsUserExists(username string) error {
+func (r *ResponsibleStore) IsUserExists(ctx context.Context, username string) error {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var userId string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id FROM employee WHERE username = $1;",
 		username,
 	).Scan(&userId)
@@ -142,44 +141,61 @@ func (r *ResponsibleStore) IsUserExists(username string) error {
 		if errors.Is(err, sql.ErrNoRows) {
 			return store.ErrUserNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return store.ErrConnClosed
-		}
-		return err
+		return pgerr.Translate(err)
 	}
 	return nil
 }
 
-func (r *ResponsibleStore) GetOrgId(username string) (string, error) {
-	userId, err := r.GetUserId(username)
+func (r *ResponsibleStore) GetOrgIds(ctx context.Context, username string) ([]string, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
+	userId, err := r.GetUserId(ctx, username)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return r.ResponcibleForOrg(userId)
+	return r.ResponcibleForOrgs(ctx, userId)
 }
 
-func (r *ResponsibleStore) ResponcibleForOrg(userId string) (string, error) {
-	var orgId string
-	err := r.db.QueryRow(
-		"SELECT organization_id FROM organization_responsible WHERE user_id = $1;",
+func (r *ResponsibleStore) ResponcibleForOrgs(ctx context.Context, userId string) ([]string, error) {
+	ctx, cancel := r.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT organization_id FROM organization_responsible WHERE user_id = $1 ORDER BY organization_id;",
 		userId,
-	).Scan(&orgId)
+	)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", store.ErrRecordNotFound
-		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return "", store.ErrConnClosed
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	var orgIds []string
+	for rows.Next() {
+		var orgId string
+		err = rows.Scan(&orgId)
+		if err != nil {
+			return nil, pgerr.Translate(err)
 		}
-		return "", err
+		orgIds = append(orgIds, orgId)
 	}
-	return orgId, nil
+	if err = rows.Err(); err != nil {
+		return nil, pgerr.Translate(err)
+	}
+
+	if len(orgIds) == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+	return orgIds, nil
 }
 
-func (r *ResponsibleStore) GetUserId(username string) (string, error) {
+func (r *ResponsibleStore) GetUserId(ctx context.Context, username string) (string, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var userId string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id FROM employee WHERE username = $1;",
 		username,
 	).Scan(&userId)
@@ -187,11 +203,78 @@ func (r *ResponsibleStore) GetUserId(username string) (string, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", store.ErrUserNotFound
 		}
-		if errors.Is(err, sql.ErrConnDone) {
-			return "", store.ErrConnClosed
-		}
-		return "", err
+		return "", pgerr.Translate(err)
 	}
 
 	return userId, nil
 }
+
+func (r *ResponsibleStore) GetRoles(ctx context.Context, userId string) (map[string]string, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT organization_id, role FROM organization_responsible WHERE user_id = $1;",
+		userId,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	roles := map[string]string{}
+	for rows.Next() {
+		var orgId, role string
+		err = rows.Scan(&orgId, &role)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		roles[orgId] = role
+	}
+	if err = rows.Err(); err != nil {
+		return nil, pgerr.Translate(err)
+	}
+
+	if len(roles) == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+	return roles, nil
+}
+
+func (r *ResponsibleStore) CountResponsibles(ctx context.Context, orgId string, roles []string) (int64, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM organization_responsible WHERE organization_id = $1 AND role::text = ANY($2);",
+		orgId,
+		pq.Array(roles),
+	).Scan(&count)
+	if err != nil {
+		return 0, pgerr.Translate(err)
+	}
+	return count, nil
+}
+
+func (r *ResponsibleStore) GetEmployee(ctx context.Context, userId string) (*models.Employee, error) {
+	ctx, cancel := r.timeouts.ForRead(ctx)
+	defer cancel()
+
+	var emp models.Employee
+	var firstName, lastName sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, username, first_name, last_name FROM employee WHERE id = $1;",
+		userId,
+	).Scan(&emp.Id, &emp.Username, &firstName, &lastName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrUserNotFound
+		}
+		return nil, pgerr.Translate(err)
+	}
+	emp.FirstName = firstName.String
+	emp.LastName = lastName.String
+
+	return &emp, nil
+}
@@ -1,38 +1,192 @@
 package store
 
-import "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+import (
+	"context"
+	"database/sql"
+	"io"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+)
 
 const Latest = -1
 
+// Orders of tender bids list
+const (
+	BidsByName     = ""
+	BidsByPrice    = "price"
+	BidsByDelivery = "delivery"
+)
+
+// Querier is implemented by both *sql.DB and *sql.Tx, so sql stores
+// work the same way inside and outside of transaction
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Timeouts bound single operation of sql stores, zero duration means no bound
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// ForRead returns ctx of reading operation, cancel is called once operation is done.
+// Queries made with returned ctx outside of transaction may be served by Replicated replica.
+func (t Timeouts) ForRead(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := bound(ctx, t.Read)
+	return context.WithValue(ctx, readOnlyKey{}, true), cancel
+}
+
+// ForWrite returns ctx of modifying operation, cancel is called once operation is done
+func (t Timeouts) ForWrite(ctx context.Context) (context.Context, context.CancelFunc) {
+	return bound(ctx, t.Write)
+}
+
+func bound(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Repositories is set of stores bound to one unit of work
+type Repositories struct {
+	Tenders      Tenders
+	Bids         Bids
+	Responsibles Responsibles
+	Audit        Audit
+	Evaluations  Evaluations
+	Attachments  Attachments
+}
+
+// UnitOfWork runs fn atomically: changes made through given repositories
+// are committed when fn returns nil and discarded otherwise
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
 type Tenders interface {
-	GetLimitedList(limit, offset int64, servType []string) ([]*models.Tender, error)
-	Create(tnd *models.Tender, resp *models.Responsible) (*models.Tender, error)
-	GetUserTenders(limit, offset int64, username string) ([]*models.Tender, error)
-	GetStatus(tenderId string) (string, error)
-	GetTenderLatestVersion(tenderId, username string) (int64, error)
-	GetCondition(tenderId string, version int64) (*models.Tender, error)
-	UpdateCondition(newCondition *models.Tender) (*models.Tender, error)
-	IsResponcibleFor(tenderId string, respUUIDs []string) error
-	GetOrgIdByBidId(bidId string) (string, error)
+	GetLimitedList(ctx context.Context, limit, offset int64, servType []string) ([]*models.Tender, error)
+	Create(ctx context.Context, tnd *models.Tender, resp *models.Responsible) (*models.Tender, error)
+	GetUserTenders(ctx context.Context, limit, offset int64, username string) ([]*models.Tender, error)
+	GetStatus(ctx context.Context, tenderId string) (string, error)
+	GetTenderLatestVersion(ctx context.Context, tenderId string) (int64, error)
+	GetCondition(ctx context.Context, tenderId string, version int64) (*models.Tender, error)
+	GetVersionsList(ctx context.Context, tenderId string, limit, offset int64) ([]*models.Tender, error)
+	UpdateCondition(ctx context.Context, newCondition *models.Tender) (*models.Tender, error)
+	// GetExpired returns published tenders with deadline passed by now, the earliest deadline first
+	GetExpired(ctx context.Context, now time.Time, limit int64) ([]*models.Tender, error)
+	// TryLock locks tender until end of unit of work, false is returned without waiting
+	// when tender is locked by another unit of work or doesn't exist
+	TryLock(ctx context.Context, tenderId string) (bool, error)
+	IsResponcibleFor(ctx context.Context, tenderId string, respUUIDs []string) error
+	GetOrgIdByBidId(ctx context.Context, bidId string) (string, error)
+	// CountByStatus counts tenders by status of their latest versions
+	CountByStatus(ctx context.Context) (map[string]int64, error)
 }
 
 type Responsibles interface {
-	GetResponsibleUUID(responsibles *models.Responsible) (string, error)
-	GetRespUUIDs(username string) ([]string, error)
-	IsResponcible(responsible *models.Responsible) error
-	IsUserExists(username string) error
-	GetOrgId(username string) (string, error)
-	ResponcibleForOrg(userId string) (string, error)
-	GetUserId(username string) (string, error)
+	GetResponsibleUUID(ctx context.Context, responsibles *models.Responsible) (string, error)
+	GetRespUUIDs(ctx context.Context, username string) ([]string, error)
+	IsResponcible(ctx context.Context, responsible *models.Responsible) error
+	IsUserExists(ctx context.Context, username string) error
+	// GetOrgIds and ResponcibleForOrgs return ids of all organizations the user is responsible for
+	// ordered by id, ErrRecordNotFound is returned when there are none
+	GetOrgIds(ctx context.Context, username string) ([]string, error)
+	ResponcibleForOrgs(ctx context.Context, userId string) ([]string, error)
+	GetUserId(ctx context.Context, username string) (string, error)
+	// GetRoles returns roles of the user by ids of organizations he is responsible for,
+	// ErrRecordNotFound is returned when there are none
+	GetRoles(ctx context.Context, userId string) (map[string]string, error)
+	// CountResponsibles counts responsibles of organization having one of roles
+	CountResponsibles(ctx context.Context, orgId string, roles []string) (int64, error)
+	GetEmployee(ctx context.Context, userId string) (*models.Employee, error)
+}
+
+// Organizations manages organizations and their responsibles
+type Organizations interface {
+	Create(ctx context.Context, org *models.Organization) (*models.Organization, error)
+	Get(ctx context.Context, orgId string) (*models.Organization, error)
+	GetList(ctx context.Context, limit, offset int64) ([]*models.Organization, error)
+	Update(ctx context.Context, org *models.Organization) (*models.Organization, error)
+	// Delete returns ErrRecordInUse while organization has tenders, bids or audit entries
+	Delete(ctx context.Context, orgId string) error
+	// Grant returns ErrRecordAlreadyExists when employee is already responsible for organization
+	Grant(ctx context.Context, orgId, userId, role string) error
+	// SetRole returns ErrRecordNotFound when employee isn't responsible for organization
+	SetRole(ctx context.Context, orgId, userId, role string) error
+	// Revoke returns ErrRecordNotFound when employee isn't responsible for organization
+	Revoke(ctx context.Context, orgId, userId string) error
+	GetResponsibles(ctx context.Context, orgId string) ([]*models.Member, error)
+}
+
+// Employees manages employees, unknown ones are reported by ErrUserNotFound
+type Employees interface {
+	// Create returns ErrRecordAlreadyExists when username is taken
+	Create(ctx context.Context, emp *models.Employee) (*models.Employee, error)
+	Get(ctx context.Context, userId string) (*models.Employee, error)
+	GetList(ctx context.Context, limit, offset int64) ([]*models.Employee, error)
+	// Update changes names of employee, username is immutable as tenders refer to it
+	Update(ctx context.Context, emp *models.Employee) (*models.Employee, error)
+	// Delete returns ErrRecordInUse while employee has tenders, bids or audit entries
+	Delete(ctx context.Context, userId string) error
 }
 
 type Bids interface {
-	Create(bid *models.Bid, orgId string) (*models.Bid, error)
-	GetUserList(limit, offset int64, userId string) ([]*models.Bid, error)
-	GetTenderList(limit, offset int64, tenderId, orgId string) ([]*models.Bid, error)
-	GetCondition(bidId string, version int64) (*models.Bid, error)
-	GetBidLatestVersion(bidId string) (int64, error)
-	UpdateCondition(newCondition *models.Bid) (*models.Bid, error)
-	AddFeedback(bidId, userId, feedback string) error
-	GetFeedbacks(tenderId, authorUserId string, limit, offset int64) ([]*models.Feedback, error)
+	Create(ctx context.Context, bid *models.Bid, orgId string) (*models.Bid, error)
+	GetUserList(ctx context.Context, limit, offset int64, userId string) ([]*models.Bid, error)
+	// GetTenderList returns published bids of tender and bids of any organization from orgIds,
+	// bids are ordered by one of BidsBy orders, price order groups bids by currency
+	GetTenderList(ctx context.Context, limit, offset int64, tenderId string, orgIds []string, order string) ([]*models.Bid, error)
+	// GetRanking returns published bids with price ordered by currency, amount, delivery days and creation time
+	GetRanking(ctx context.Context, tenderId string) ([]*models.Bid, error)
+	// GetPublishedList returns all published bids of tender in creation order
+	GetPublishedList(ctx context.Context, tenderId string) ([]*models.Bid, error)
+	GetCondition(ctx context.Context, bidId string, version int64) (*models.Bid, error)
+	GetVersionsList(ctx context.Context, bidId string, limit, offset int64) ([]*models.Bid, error)
+	GetBidLatestVersion(ctx context.Context, bidId string) (int64, error)
+	UpdateCondition(ctx context.Context, newCondition *models.Bid) (*models.Bid, error)
+	AddFeedback(ctx context.Context, bidId, userId, feedback string) error
+	GetFeedbacks(ctx context.Context, tenderId, authorUsername string, limit, offset int64) ([]*models.Feedback, error)
+	// Lock makes concurrent units of work locking the same bid wait until the one holding lock
+	// is done, it returns ErrRecordNotFound when bid doesn't exist
+	Lock(ctx context.Context, bidId string) error
+	AddDecision(ctx context.Context, bidId, userId, decision string) error
+	GetDecisionsCount(ctx context.Context, bidId string) (approved, rejected int64, err error)
+	// CountByStatus counts bids by status of their latest versions
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+type Audit interface {
+	Add(ctx context.Context, entry *models.AuditEntry) error
+	GetOrgList(ctx context.Context, orgId string, limit, offset int64) ([]*models.AuditEntry, error)
+}
+
+type Evaluations interface {
+	// SetCriteria replaces criteria of tender, scores given by replaced criteria are dropped
+	SetCriteria(ctx context.Context, tenderId string, criteria []*models.Criterion) ([]*models.Criterion, error)
+	GetCriteria(ctx context.Context, tenderId string) ([]*models.Criterion, error)
+	// SetScore adds score or replaces one given before by the same evaluator
+	SetScore(ctx context.Context, score *models.Score) error
+	// GetScores returns scores of all bids of tender
+	GetScores(ctx context.Context, tenderId string) ([]*models.Score, error)
+}
+
+type Attachments interface {
+	Add(ctx context.Context, att *models.Attachment) (*models.Attachment, error)
+	// GetList returns attachments of tender or bid in upload order
+	GetList(ctx context.Context, entityType, entityId string) ([]*models.Attachment, error)
+	Get(ctx context.Context, attachmentId string) (*models.Attachment, error)
+}
+
+// BlobStore keeps file contents addressed by their sha256 digest, equal contents are stored once.
+// Blobs aren't part of unit of work: content is put before its attachment record is written
+type BlobStore interface {
+	// Put saves content read from r and returns its hex encoded sha256 digest and size,
+	// error returned by r is passed through wrapped
+	Put(ctx context.Context, r io.Reader) (digest string, size int64, err error)
+	// Open returns ErrRecordNotFound when there is no content with such digest
+	Open(ctx context.Context, digest string) (io.ReadCloser, error)
 }
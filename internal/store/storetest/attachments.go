@@ -0,0 +1,115 @@
+package storetest
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func attachmentName(a *models.Attachment) string { return a.Name }
+
+func runAttachments(t *testing.T, newStores Factory) {
+	t.Run("List", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		bid := createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "Offer")
+
+		spec := addAttachment(t, s, models.AttachmentTender, tnd.Id, f.Responsible.Id, "spec.pdf")
+		addAttachment(t, s, models.AttachmentTender, tnd.Id, f.Colleague.Id, "drawing.dwg")
+		addAttachment(t, s, models.AttachmentBid, bid.Id, f.Competitor.Id, "prices.xlsx")
+
+		got, err := s.Attachments.GetList(ctx, models.AttachmentTender, tnd.Id)
+		if err != nil {
+			t.Fatalf("GetList: %s", err)
+		}
+		expectNames(t, "GetList tender", names(got, attachmentName), []string{"spec.pdf", "drawing.dwg"})
+
+		got, err = s.Attachments.GetList(ctx, models.AttachmentBid, bid.Id)
+		if err != nil {
+			t.Fatalf("GetList: %s", err)
+		}
+		expectNames(t, "GetList bid", names(got, attachmentName), []string{"prices.xlsx"})
+
+		att, err := s.Attachments.Get(ctx, spec.Id)
+		if err != nil {
+			t.Fatalf("Get: %s", err)
+		}
+		if att.EntityType != models.AttachmentTender || att.EntityId != tnd.Id || att.AuthorId != f.Responsible.Id || att.Digest != spec.Digest || att.Size != spec.Size {
+			t.Fatalf("Get: unexpected attachment %+v", att)
+		}
+
+		_, err = s.Attachments.Get(ctx, unknownId)
+		expectErr(t, "Get unknown", err, store.ErrRecordNotFound)
+	})
+
+	t.Run("Blobs", func(t *testing.T) {
+		s, _ := newStores(t)
+
+		digest, size, err := s.Blobs.Put(ctx, strings.NewReader("drawing"))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+		if size != 7 || len(digest) != 64 {
+			t.Fatalf("Put: unexpected digest %q and size %d", digest, size)
+		}
+
+		again, _, err := s.Blobs.Put(ctx, strings.NewReader("drawing"))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+		if again != digest {
+			t.Fatalf("Put: expected equal content to get digest %q, got %q", digest, again)
+		}
+
+		other, _, err := s.Blobs.Put(ctx, strings.NewReader("spec"))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+		if other == digest {
+			t.Fatalf("Put: different contents got same digest %q", digest)
+		}
+
+		r, err := s.Blobs.Open(ctx, digest)
+		if err != nil {
+			t.Fatalf("Open: %s", err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil || string(content) != "drawing" {
+			t.Fatalf("Open: expected content %q, got %q, %v", "drawing", content, err)
+		}
+
+		_, err = s.Blobs.Open(ctx, strings.Repeat("0", 64))
+		expectErr(t, "Open unknown", err, store.ErrRecordNotFound)
+	})
+}
+
+func addAttachment(t *testing.T, s Stores, entityType, entityId, authorId, name string) *models.Attachment {
+	t.Helper()
+
+	digest, size, err := s.Blobs.Put(ctx, strings.NewReader(name+" content"))
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	att, err := s.Attachments.Add(ctx, &models.Attachment{
+		EntityType:  entityType,
+		EntityId:    entityId,
+		AuthorId:    authorId,
+		Name:        name,
+		ContentType: "application/octet-stream",
+		Size:        size,
+		Digest:      digest,
+	})
+	if err != nil {
+		t.Fatalf("Add: %s", err)
+	}
+	if att.Id == "" || att.Created.IsZero() {
+		t.Fatalf("Add: unexpected attachment %+v", att)
+	}
+	return att
+}
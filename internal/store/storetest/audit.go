@@ -0,0 +1,89 @@
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func runAudit(t *testing.T, newStores Factory) {
+	t.Run("OrgList", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		for _, action := range []string{models.AuditCreate, models.AuditEdit, models.AuditRollback} {
+			addAudit(t, s.Audit, f.OrgId, f.Responsible.Id, action, tnd.Id)
+		}
+		addAudit(t, s.Audit, f.OtherOrgId, f.Competitor.Id, models.AuditCreate, unknownId)
+
+		list, err := s.Audit.GetOrgList(ctx, f.OrgId, 10, 0)
+		if err != nil {
+			t.Fatalf("GetOrgList: %s", err)
+		}
+		expectActions(t, list, models.AuditRollback, models.AuditEdit, models.AuditCreate)
+		if list[0].Id == "" || list[0].Created.IsZero() || list[0].ActorId != f.Responsible.Id || list[0].NewVersion != 2 {
+			t.Fatalf("GetOrgList: unexpected entry %+v", list[0])
+		}
+
+		list, err = s.Audit.GetOrgList(ctx, f.OrgId, 1, 1)
+		if err != nil {
+			t.Fatalf("GetOrgList page: %s", err)
+		}
+		expectActions(t, list, models.AuditEdit)
+
+		list, err = s.Audit.GetOrgList(ctx, f.OtherOrgId, 10, 0)
+		if err != nil {
+			t.Fatalf("GetOrgList other: %s", err)
+		}
+		expectActions(t, list, models.AuditCreate)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		s, f := newStores(t)
+		errAbort := errors.New("abort")
+
+		err := s.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+			addAudit(t, repos.Audit, f.OrgId, f.Responsible.Id, models.AuditCreate, unknownId)
+			return errAbort
+		})
+		expectErr(t, "Do", err, errAbort)
+
+		list, err := s.Audit.GetOrgList(ctx, f.OrgId, 10, 0)
+		if err != nil {
+			t.Fatalf("GetOrgList: %s", err)
+		}
+		expectActions(t, list)
+	})
+}
+
+func addAudit(t *testing.T, a store.Audit, orgId, actorId, action, entityId string) {
+	t.Helper()
+
+	err := a.Add(ctx, &models.AuditEntry{
+		ActorId:    actorId,
+		OrgId:      orgId,
+		Action:     action,
+		EntityType: models.AuditTender,
+		EntityId:   entityId,
+		OldVersion: 1,
+		NewVersion: 2,
+	})
+	if err != nil {
+		t.Fatalf("Add audit entry: %s", err)
+	}
+}
+
+func expectActions(t *testing.T, list []*models.AuditEntry, actions ...string) {
+	t.Helper()
+
+	if len(list) != len(actions) {
+		t.Fatalf("expected %d audit entries, got %d", len(actions), len(list))
+	}
+	for i, entry := range list {
+		if entry.Action != actions[i] {
+			t.Fatalf("expected action %s at %d, got %s", actions[i], i, entry.Action)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func bidName(b *models.Bid) string { return b.Name }
+
+func runBids(t *testing.T, newStores Factory) {
+	t.Run("CreateAndGetCondition", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+
+		userBid := createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "Personal")
+		if userBid.Id == "" || userBid.Version != 1 || userBid.Status != "Created" || userBid.Created.IsZero() {
+			t.Fatalf("Create: unexpected bid %+v", userBid)
+		}
+
+		got, err := s.Bids.GetCondition(ctx, userBid.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition: %s", err)
+		}
+		if got.TenderId != tnd.Id || got.AuthorType != "User" || got.AuthorId != f.Competitor.Id {
+			t.Fatalf("GetCondition: unexpected bid %+v", got)
+		}
+
+		orgBid := createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "Corporate")
+		got, err = s.Bids.GetCondition(ctx, orgBid.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition: %s", err)
+		}
+		if got.AuthorType != "Organization" || got.AuthorId != f.OtherOrgId {
+			t.Fatalf("GetCondition: unexpected bid %+v", got)
+		}
+
+		_, err = s.Bids.GetCondition(ctx, unknownId, store.Latest)
+		expectErr(t, "GetCondition", err, store.ErrRecordNotFound)
+		_, err = s.Bids.GetBidLatestVersion(ctx, unknownId)
+		expectErr(t, "GetBidLatestVersion", err, store.ErrRecordNotFound)
+	})
+
+	t.Run("VersionHistory", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		bid := createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "Personal")
+
+		next := *bid
+		next.Name = "Renamed"
+		next.Version = 2
+		_, err := s.Bids.UpdateCondition(ctx, &next)
+		if err != nil {
+			t.Fatalf("UpdateCondition: %s", err)
+		}
+
+		latest, err := s.Bids.GetCondition(ctx, bid.Id, store.Latest)
+		if err != nil || latest.Version != 2 || latest.Name != "Renamed" {
+			t.Fatalf("GetCondition latest: unexpected bid %+v, %v", latest, err)
+		}
+
+		first, err := s.Bids.GetCondition(ctx, bid.Id, 1)
+		if err != nil || first.Version != 1 || first.Name != "Personal" {
+			t.Fatalf("GetCondition 1: unexpected bid %+v, %v", first, err)
+		}
+
+		_, err = s.Bids.GetCondition(ctx, bid.Id, 3)
+		expectErr(t, "GetCondition", err, store.ErrRecordNotFound)
+
+		versions, err := s.Bids.GetVersionsList(ctx, bid.Id, 10, 0)
+		if err != nil {
+			t.Fatalf("GetVersionsList: %s", err)
+		}
+		expectNames(t, "GetVersionsList", names(versions, bidName), []string{"Personal", "Renamed"})
+		if versions[1].Version != 2 || versions[1].TenderId != tnd.Id {
+			t.Fatalf("GetVersionsList: unexpected version %+v", versions[1])
+		}
+
+		page, err := s.Bids.GetVersionsList(ctx, bid.Id, 1, 1)
+		if err != nil {
+			t.Fatalf("GetVersionsList: %s", err)
+		}
+		expectNames(t, "GetVersionsList page", names(page, bidName), []string{"Renamed"})
+
+		version, err := s.Bids.GetBidLatestVersion(ctx, bid.Id)
+		if err != nil || version != 2 {
+			t.Fatalf("GetBidLatestVersion: expected 2, got %d, %v", version, err)
+		}
+
+		_, err = s.Bids.UpdateCondition(ctx, &next)
+		expectErr(t, "UpdateCondition", err, store.ErrRecordAlreadyExists)
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "B")
+		published := createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "A")
+		publishBid(t, s, published.Id)
+		createBid(t, s, tnd.Id, "Organization", f.OrgId, f.OrgId, "C")
+
+		mine, err := s.Bids.GetUserList(ctx, 10, 0, f.Competitor.Id)
+		if err != nil {
+			t.Fatalf("GetUserList: %s", err)
+		}
+		expectNames(t, "GetUserList", names(mine, bidName), []string{"A", "B"})
+
+		page, err := s.Bids.GetUserList(ctx, 1, 1, f.Competitor.Id)
+		if err != nil {
+			t.Fatalf("GetUserList: %s", err)
+		}
+		expectNames(t, "GetUserList page", names(page, bidName), []string{"B"})
+
+		// unpublished bids are visible only to their organization
+		own, err := s.Bids.GetTenderList(ctx, 10, 0, tnd.Id, []string{f.OtherOrgId}, store.BidsByName)
+		if err != nil {
+			t.Fatalf("GetTenderList: %s", err)
+		}
+		expectNames(t, "GetTenderList author", names(own, bidName), []string{"A", "B"})
+
+		foreign, err := s.Bids.GetTenderList(ctx, 10, 0, tnd.Id, []string{f.OrgId}, store.BidsByName)
+		if err != nil {
+			t.Fatalf("GetTenderList: %s", err)
+		}
+		expectNames(t, "GetTenderList owner", names(foreign, bidName), []string{"A", "C"})
+
+		both, err := s.Bids.GetTenderList(ctx, 10, 0, tnd.Id, []string{f.OrgId, f.OtherOrgId}, store.BidsByName)
+		if err != nil {
+			t.Fatalf("GetTenderList: %s", err)
+		}
+		expectNames(t, "GetTenderList of several organizations", names(both, bidName), []string{"A", "B", "C"})
+	})
+
+	t.Run("Feedbacks", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		bid := createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "Offer")
+		publishBid(t, s, bid.Id)
+
+		err := s.Bids.AddFeedback(ctx, bid.Id, f.Responsible.Id, "first")
+		if err != nil {
+			t.Fatalf("AddFeedback: %s", err)
+		}
+		err = s.Bids.AddFeedback(ctx, bid.Id, f.Colleague.Id, "second")
+		if err != nil {
+			t.Fatalf("AddFeedback: %s", err)
+		}
+
+		feedbacks, err := s.Bids.GetFeedbacks(ctx, tnd.Id, f.Competitor.Username, 10, 0)
+		if err != nil {
+			t.Fatalf("GetFeedbacks: %s", err)
+		}
+		if len(feedbacks) != 2 {
+			t.Fatalf("GetFeedbacks: expected 2 feedbacks, got %d", len(feedbacks))
+		}
+
+		feedbacks, err = s.Bids.GetFeedbacks(ctx, tnd.Id, f.Outsider.Username, 10, 0)
+		if err != nil {
+			t.Fatalf("GetFeedbacks: %s", err)
+		}
+		if len(feedbacks) != 0 {
+			t.Fatalf("GetFeedbacks: expected no feedbacks, got %d", len(feedbacks))
+		}
+	})
+
+	t.Run("Decisions", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		bid := createBid(t, s, tnd.Id, "User", f.Competitor.Id, f.OtherOrgId, "Offer")
+
+		err := s.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+			return repos.Bids.Lock(ctx, bid.Id)
+		})
+		if err != nil {
+			t.Fatalf("Lock: %s", err)
+		}
+		err = s.Bids.Lock(ctx, unknownId)
+		expectErr(t, "Lock", err, store.ErrRecordNotFound)
+
+		approved, rejected, err := s.Bids.GetDecisionsCount(ctx, bid.Id)
+		if err != nil || approved != 0 || rejected != 0 {
+			t.Fatalf("GetDecisionsCount: expected 0/0, got %d/%d, %v", approved, rejected, err)
+		}
+
+		err = s.Bids.AddDecision(ctx, bid.Id, f.Responsible.Id, "Approved")
+		if err != nil {
+			t.Fatalf("AddDecision: %s", err)
+		}
+		err = s.Bids.AddDecision(ctx, bid.Id, f.Colleague.Id, "Approved")
+		if err != nil {
+			t.Fatalf("AddDecision: %s", err)
+		}
+		// repeated decision replaces previous one
+		err = s.Bids.AddDecision(ctx, bid.Id, f.Colleague.Id, "Rejected")
+		if err != nil {
+			t.Fatalf("AddDecision: %s", err)
+		}
+
+		approved, rejected, err = s.Bids.GetDecisionsCount(ctx, bid.Id)
+		if err != nil || approved != 1 || rejected != 1 {
+			t.Fatalf("GetDecisionsCount: expected 1/1, got %d/%d, %v", approved, rejected, err)
+		}
+	})
+	t.Run("Pricing", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		priced := func(name, currency string, amount float64, days int64) *models.Bid {
+			t.Helper()
+
+			bid, err := s.Bids.Create(ctx, &models.Bid{
+				Name:         name,
+				Description:  name + " description",
+				TenderId:     tnd.Id,
+				AuthorType:   "Organization",
+				AuthorId:     f.OtherOrgId,
+				Amount:       &amount,
+				Currency:     currency,
+				DeliveryDays: &days,
+			}, f.OtherOrgId)
+			if err != nil {
+				t.Fatalf("Create bid: %s", err)
+			}
+			return publishBid(t, s, bid.Id)
+		}
+
+		priced("A", "RUB", 300, 5)
+		priced("B", "RUB", 100, 20)
+		priced("C", "USD", 50, 10)
+		publishBid(t, s, createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "D").Id)
+		createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "E")
+
+		got, err := s.Bids.GetCondition(ctx, priced("F", "RUB", 200.5, 1).Id, store.Latest)
+		if err != nil || got.Amount == nil || *got.Amount != 200.5 || got.Currency != "RUB" || got.DeliveryDays == nil || *got.DeliveryDays != 1 {
+			t.Fatalf("GetCondition: unexpected price of %+v, %v", got, err)
+		}
+
+		byPrice, err := s.Bids.GetTenderList(ctx, 10, 0, tnd.Id, []string{f.OrgId}, store.BidsByPrice)
+		if err != nil {
+			t.Fatalf("GetTenderList: %s", err)
+		}
+		expectNames(t, "GetTenderList by price", names(byPrice, bidName), []string{"B", "F", "A", "C", "D"})
+
+		byDelivery, err := s.Bids.GetTenderList(ctx, 10, 0, tnd.Id, []string{f.OrgId}, store.BidsByDelivery)
+		if err != nil {
+			t.Fatalf("GetTenderList: %s", err)
+		}
+		expectNames(t, "GetTenderList by delivery", names(byDelivery, bidName), []string{"F", "A", "C", "B", "D"})
+
+		ranking, err := s.Bids.GetRanking(ctx, tnd.Id)
+		if err != nil {
+			t.Fatalf("GetRanking: %s", err)
+		}
+		expectNames(t, "GetRanking", names(ranking, bidName), []string{"B", "F", "A", "C"})
+
+		published, err := s.Bids.GetPublishedList(ctx, tnd.Id)
+		if err != nil {
+			t.Fatalf("GetPublishedList: %s", err)
+		}
+		expectNames(t, "GetPublishedList", names(published, bidName), []string{"A", "B", "C", "D", "F"})
+
+		counts, err := s.Bids.CountByStatus(ctx)
+		if err != nil || len(counts) != 2 || counts["Published"] != 5 || counts["Created"] != 1 {
+			t.Fatalf("CountByStatus: expected 5 published and 1 created, got %v, %v", counts, err)
+		}
+	})
+}
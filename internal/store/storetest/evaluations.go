@@ -0,0 +1,103 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+)
+
+func criterionName(c *models.Criterion) string { return c.Name }
+
+func runEvaluations(t *testing.T, newStores Factory) {
+	t.Run("Criteria", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		other := createTender(t, s, f, "Bridges", "Construction")
+
+		criteria := setCriteria(t, s, tnd.Id, "Price", "Experience", "Warranty")
+		for _, c := range criteria {
+			if c.Id == "" || c.TenderId != tnd.Id {
+				t.Fatalf("SetCriteria: unexpected criterion %+v", c)
+			}
+		}
+
+		got, err := s.Evaluations.GetCriteria(ctx, tnd.Id)
+		if err != nil {
+			t.Fatalf("GetCriteria: %s", err)
+		}
+		expectNames(t, "GetCriteria", names(got, criterionName), []string{"Price", "Experience", "Warranty"})
+		if got[0].Weight != 60 || got[1].Weight != 25 || got[2].Weight != 15 {
+			t.Fatalf("GetCriteria: unexpected weights %+v %+v %+v", got[0], got[1], got[2])
+		}
+
+		got, err = s.Evaluations.GetCriteria(ctx, other.Id)
+		if err != nil {
+			t.Fatalf("GetCriteria: %s", err)
+		}
+		expectNames(t, "GetCriteria other tender", names(got, criterionName), []string{})
+	})
+
+	t.Run("Scores", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		bid := createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "Offer")
+		criteria := setCriteria(t, s, tnd.Id, "Price", "Experience", "Warranty")
+
+		setScore(t, s, bid.Id, criteria[0].Id, f.Responsible.Id, 10)
+		setScore(t, s, bid.Id, criteria[0].Id, f.Colleague.Id, 70)
+		// evaluator may change his score
+		setScore(t, s, bid.Id, criteria[0].Id, f.Responsible.Id, 90)
+		setScore(t, s, bid.Id, criteria[1].Id, f.Responsible.Id, 40)
+
+		scores, err := s.Evaluations.GetScores(ctx, tnd.Id)
+		if err != nil {
+			t.Fatalf("GetScores: %s", err)
+		}
+		if len(scores) != 3 {
+			t.Fatalf("GetScores: expected 3 scores, got %d", len(scores))
+		}
+		total := models.ScoreBid(bid.Id, criteria, scores).Total
+		// price (90 + 70) / 2 * 0.6 + experience 40 * 0.25
+		if total != 58 {
+			t.Fatalf("ScoreBid: expected total 58, got %v", total)
+		}
+
+		// replaced criteria take their scores with them
+		setCriteria(t, s, tnd.Id, "Price", "Experience", "Warranty")
+		scores, err = s.Evaluations.GetScores(ctx, tnd.Id)
+		if err != nil || len(scores) != 0 {
+			t.Fatalf("GetScores: expected no scores after criteria replace, got %d, %v", len(scores), err)
+		}
+	})
+}
+
+func setCriteria(t *testing.T, s Stores, tenderId string, criterionNames ...string) []*models.Criterion {
+	t.Helper()
+
+	weights := []int64{60, 25, 15}
+	criteria := make([]*models.Criterion, 0, len(criterionNames))
+	for i, name := range criterionNames {
+		criteria = append(criteria, &models.Criterion{Name: name, Weight: weights[i]})
+	}
+	criteria, err := s.Evaluations.SetCriteria(ctx, tenderId, criteria)
+	if err != nil {
+		t.Fatalf("SetCriteria: %s", err)
+	}
+	return criteria
+}
+
+func setScore(t *testing.T, s Stores, bidId, criterionId, evaluatorId string, value int64) {
+	t.Helper()
+
+	err := s.Evaluations.SetScore(ctx, &models.Score{
+		BidId:       bidId,
+		CriterionId: criterionId,
+		EvaluatorId: evaluatorId,
+		Value:       value,
+	})
+	if err != nil {
+		t.Fatalf("SetScore: %s", err)
+	}
+}
@@ -0,0 +1,43 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/memstore"
+)
+
+// NewMemory is Factory of memstore backed stores, also useful for services tests
+func NewMemory(t *testing.T) (Stores, Fixture) {
+	db := memstore.NewDB()
+
+	f := Fixture{
+		OrgId:       db.AddOrganization("", "Buyer"),
+		OtherOrgId:  db.AddOrganization("", "Supplier"),
+		Responsible: models.Employee{Username: "responsible"},
+		Colleague:   models.Employee{Username: "colleague"},
+		Competitor:  models.Employee{Username: "competitor"},
+		Outsider:    models.Employee{Username: "outsider"},
+	}
+	f.Responsible.Id = db.AddEmployee(f.Responsible)
+	f.Colleague.Id = db.AddEmployee(f.Colleague)
+	f.Competitor.Id = db.AddEmployee(f.Competitor)
+	f.Outsider.Id = db.AddEmployee(f.Outsider)
+
+	db.AddResponsible(f.OrgId, f.Responsible.Id, "")
+	db.AddResponsible(f.OrgId, f.Colleague.Id, "")
+	db.AddResponsible(f.OtherOrgId, f.Competitor.Id, "")
+
+	return Stores{
+		Tenders:       memstore.NewTenderStore(db),
+		Bids:          memstore.NewBidStore(db),
+		Responsibles:  memstore.NewResponsibleStore(db),
+		Organizations: memstore.NewOrganizationStore(db),
+		Employees:     memstore.NewEmployeeStore(db),
+		Audit:         memstore.NewAuditStore(db),
+		Evaluations:   memstore.NewEvaluationStore(db),
+		Attachments:   memstore.NewAttachmentStore(db),
+		Blobs:         memstore.NewBlobStore(),
+		UnitOfWork:    memstore.NewUnitOfWork(db),
+	}, f
+}
@@ -0,0 +1,175 @@
+package storetest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func organizationName(o *models.Organization) string { return o.Name }
+
+func employeeUsername(e *models.Employee) string { return e.Username }
+
+func memberUsername(m *models.Member) string { return m.Username + ":" + m.Role }
+
+func runOrganizations(t *testing.T, newStores Factory) {
+	t.Run("CRUD", func(t *testing.T) {
+		s, f := newStores(t)
+
+		org, err := s.Organizations.Create(ctx, &models.Organization{Name: "Carrier", Description: "Trucks", Type: models.OrgTypeLLC})
+		if err != nil {
+			t.Fatalf("Create: %s", err)
+		}
+		if org.Id == "" || org.Created.IsZero() {
+			t.Fatalf("Create: unexpected organization %+v", org)
+		}
+
+		got, err := s.Organizations.Get(ctx, org.Id)
+		if err != nil || got.Name != "Carrier" || got.Description != "Trucks" || got.Type != models.OrgTypeLLC {
+			t.Fatalf("Get: unexpected organization %+v, %v", got, err)
+		}
+		_, err = s.Organizations.Get(ctx, unknownId)
+		expectErr(t, "Get unknown", err, store.ErrRecordNotFound)
+
+		list, err := s.Organizations.GetList(ctx, 2, 0)
+		if err != nil {
+			t.Fatalf("GetList: %s", err)
+		}
+		expectNames(t, "GetList", names(list, organizationName), []string{"Buyer", "Carrier"})
+		list, err = s.Organizations.GetList(ctx, 5, 2)
+		if err != nil {
+			t.Fatalf("GetList: %s", err)
+		}
+		expectNames(t, "GetList offset", names(list, organizationName), []string{"Supplier"})
+
+		got.Name = "Shipper"
+		got.Type = ""
+		got, err = s.Organizations.Update(ctx, got)
+		if err != nil || got.Name != "Shipper" || got.Type != "" || got.Created.IsZero() {
+			t.Fatalf("Update: unexpected organization %+v, %v", got, err)
+		}
+		_, err = s.Organizations.Update(ctx, &models.Organization{Id: unknownId, Name: "Nobody"})
+		expectErr(t, "Update unknown", err, store.ErrRecordNotFound)
+
+		err = s.Organizations.Grant(ctx, org.Id, f.Outsider.Id, "admin")
+		if err != nil {
+			t.Fatalf("Grant: %s", err)
+		}
+		err = s.Organizations.Delete(ctx, org.Id)
+		if err != nil {
+			t.Fatalf("Delete: %s", err)
+		}
+		_, err = s.Organizations.Get(ctx, org.Id)
+		expectErr(t, "Get deleted", err, store.ErrRecordNotFound)
+		_, err = s.Responsibles.ResponcibleForOrgs(ctx, f.Outsider.Id)
+		expectErr(t, "ResponcibleForOrgs of deleted organization", err, store.ErrRecordNotFound)
+
+		err = s.Organizations.Delete(ctx, org.Id)
+		expectErr(t, "Delete deleted", err, store.ErrRecordNotFound)
+
+		// organization with tenders keeps its history
+		createTender(t, s, f, "Roads", "Construction")
+		err = s.Organizations.Delete(ctx, f.OrgId)
+		expectErr(t, "Delete organization with tenders", err, store.ErrRecordInUse)
+	})
+
+	t.Run("Responsibles", func(t *testing.T) {
+		s, f := newStores(t)
+
+		got, err := s.Organizations.GetResponsibles(ctx, f.OrgId)
+		if err != nil {
+			t.Fatalf("GetResponsibles: %s", err)
+		}
+		expectNames(t, "GetResponsibles", names(got, memberUsername), []string{"colleague:admin", "responsible:admin"})
+
+		err = s.Organizations.Grant(ctx, f.OrgId, f.Outsider.Id, "viewer")
+		if err != nil {
+			t.Fatalf("Grant: %s", err)
+		}
+		err = s.Organizations.Grant(ctx, f.OrgId, f.Outsider.Id, "editor")
+		expectErr(t, "Grant twice", err, store.ErrRecordAlreadyExists)
+
+		roles, err := s.Responsibles.GetRoles(ctx, f.Outsider.Id)
+		if err != nil || len(roles) != 1 || roles[f.OrgId] != "viewer" {
+			t.Fatalf("GetRoles: unexpected %v, %v", roles, err)
+		}
+		err = s.Organizations.SetRole(ctx, f.OrgId, f.Outsider.Id, "approver")
+		if err != nil {
+			t.Fatalf("SetRole: %s", err)
+		}
+		err = s.Organizations.SetRole(ctx, f.OtherOrgId, f.Outsider.Id, "approver")
+		expectErr(t, "SetRole of not responsible", err, store.ErrRecordNotFound)
+
+		count, err := s.Responsibles.CountResponsibles(ctx, f.OrgId, []string{"approver"})
+		if err != nil || count != 1 {
+			t.Fatalf("CountResponsibles: expected 1, got %d, %v", count, err)
+		}
+
+		orgIds, err := s.Responsibles.ResponcibleForOrgs(ctx, f.Outsider.Id)
+		if err != nil || !slices.Equal(orgIds, []string{f.OrgId}) {
+			t.Fatalf("ResponcibleForOrgs: expected [%s], got %v, %v", f.OrgId, orgIds, err)
+		}
+
+		err = s.Organizations.Revoke(ctx, f.OrgId, f.Colleague.Id)
+		if err != nil {
+			t.Fatalf("Revoke: %s", err)
+		}
+		err = s.Organizations.Revoke(ctx, f.OrgId, f.Colleague.Id)
+		expectErr(t, "Revoke twice", err, store.ErrRecordNotFound)
+
+		got, err = s.Organizations.GetResponsibles(ctx, f.OrgId)
+		if err != nil {
+			t.Fatalf("GetResponsibles: %s", err)
+		}
+		expectNames(t, "GetResponsibles after grant and revoke", names(got, memberUsername), []string{"outsider:approver", "responsible:admin"})
+	})
+}
+
+func runEmployees(t *testing.T, newStores Factory) {
+	t.Run("CRUD", func(t *testing.T) {
+		s, f := newStores(t)
+
+		emp, err := s.Employees.Create(ctx, &models.Employee{Username: "newcomer", FirstName: "Ivan"})
+		if err != nil || emp.Id == "" {
+			t.Fatalf("Create: unexpected employee %+v, %v", emp, err)
+		}
+		_, err = s.Employees.Create(ctx, &models.Employee{Username: "newcomer"})
+		expectErr(t, "Create taken username", err, store.ErrRecordAlreadyExists)
+
+		got, err := s.Employees.Get(ctx, emp.Id)
+		if err != nil || got.Username != "newcomer" || got.FirstName != "Ivan" {
+			t.Fatalf("Get: unexpected employee %+v, %v", got, err)
+		}
+		_, err = s.Employees.Get(ctx, unknownId)
+		expectErr(t, "Get unknown", err, store.ErrUserNotFound)
+
+		list, err := s.Employees.GetList(ctx, 3, 1)
+		if err != nil {
+			t.Fatalf("GetList: %s", err)
+		}
+		expectNames(t, "GetList", names(list, employeeUsername), []string{"competitor", "newcomer", "outsider"})
+
+		got, err = s.Employees.Update(ctx, &models.Employee{Id: emp.Id, FirstName: "Ivan", LastName: "Petrov"})
+		if err != nil || got.Username != "newcomer" || got.LastName != "Petrov" {
+			t.Fatalf("Update: unexpected employee %+v, %v", got, err)
+		}
+		_, err = s.Employees.Update(ctx, &models.Employee{Id: unknownId})
+		expectErr(t, "Update unknown", err, store.ErrUserNotFound)
+
+		err = s.Employees.Delete(ctx, emp.Id)
+		if err != nil {
+			t.Fatalf("Delete: %s", err)
+		}
+		_, err = s.Employees.Get(ctx, emp.Id)
+		expectErr(t, "Get deleted", err, store.ErrUserNotFound)
+		err = s.Employees.Delete(ctx, emp.Id)
+		expectErr(t, "Delete deleted", err, store.ErrUserNotFound)
+
+		// employee with tenders keeps his history
+		createTender(t, s, f, "Roads", "Construction")
+		err = s.Employees.Delete(ctx, f.Responsible.Id)
+		expectErr(t, "Delete employee with tenders", err, store.ErrRecordInUse)
+	})
+}
@@ -0,0 +1,111 @@
+package storetest
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/policy"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/attachmentstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/auditstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/bidstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/blobstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/employeestore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/evaluationstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/organizationstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/responsiblestore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/tenderstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/txstore"
+	_ "github.com/lib/pq"
+)
+
+// PostgresEnv names variable with connection string of database NewPostgres runs on.
+// Database has to be migrated, all its records are deleted before every test case.
+const PostgresEnv = "POSTGRES_TEST_CONN"
+
+// NewPostgres is Factory of sql stores, test is skipped when PostgresEnv is unset
+func NewPostgres(t *testing.T) (Stores, Fixture) {
+	conn, ok := os.LookupEnv(PostgresEnv)
+	if !ok {
+		t.Skipf("%s is not set", PostgresEnv)
+	}
+
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		t.Fatalf("open database: %s", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	// audit_log has no references to cascade from
+	_, err = db.ExecContext(ctx, "TRUNCATE organization, employee, audit_log CASCADE;")
+	if err != nil {
+		t.Fatalf("clean database: %s", err)
+	}
+
+	timeouts := store.Timeouts{}
+	organizations := organizationstore.New(db, timeouts)
+	employees := employeestore.New(db, timeouts)
+
+	f := Fixture{
+		OrgId:       createOrganization(t, organizations, "Buyer"),
+		OtherOrgId:  createOrganization(t, organizations, "Supplier"),
+		Responsible: createEmployee(t, employees, "responsible"),
+		Colleague:   createEmployee(t, employees, "colleague"),
+		Competitor:  createEmployee(t, employees, "competitor"),
+		Outsider:    createEmployee(t, employees, "outsider"),
+	}
+	grant(t, organizations, f.OrgId, f.Responsible.Id)
+	grant(t, organizations, f.OrgId, f.Colleague.Id)
+	grant(t, organizations, f.OtherOrgId, f.Competitor.Id)
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %s", err)
+	}
+
+	return Stores{
+		Tenders:       tenderstore.New(db, timeouts),
+		Bids:          bidstore.New(db, timeouts),
+		Responsibles:  responsiblestore.New(db, timeouts),
+		Organizations: organizations,
+		Employees:     employees,
+		Audit:         auditstore.New(db, timeouts),
+		Evaluations:   evaluationstore.New(db, timeouts),
+		Attachments:   attachmentstore.New(db, timeouts),
+		Blobs:         blobs,
+		UnitOfWork:    txstore.New(db, timeouts, nil),
+	}, f
+}
+
+func createOrganization(t *testing.T, organizations store.Organizations, name string) string {
+	t.Helper()
+
+	org, err := organizations.Create(ctx, &models.Organization{Name: name, Type: models.OrgTypeLLC})
+	if err != nil {
+		t.Fatalf("Create organization: %s", err)
+	}
+	return org.Id
+}
+
+func createEmployee(t *testing.T, employees store.Employees, username string) models.Employee {
+	t.Helper()
+
+	emp, err := employees.Create(ctx, &models.Employee{Username: username})
+	if err != nil {
+		t.Fatalf("Create employee: %s", err)
+	}
+	return *emp
+}
+
+func grant(t *testing.T, organizations store.Organizations, orgId, userId string) {
+	t.Helper()
+
+	err := organizations.Grant(ctx, orgId, userId, policy.RoleAdmin)
+	if err != nil {
+		t.Fatalf("Grant: %s", err)
+	}
+}
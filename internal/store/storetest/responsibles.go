@@ -0,0 +1,100 @@
+package storetest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func runResponsibles(t *testing.T, newStores Factory) {
+	t.Run("Employees", func(t *testing.T) {
+		s, f := newStores(t)
+
+		userId, err := s.Responsibles.GetUserId(ctx, f.Responsible.Username)
+		if err != nil || userId != f.Responsible.Id {
+			t.Fatalf("GetUserId: expected %s, got %s, %v", f.Responsible.Id, userId, err)
+		}
+		_, err = s.Responsibles.GetUserId(ctx, "no_such_user")
+		expectErr(t, "GetUserId", err, store.ErrUserNotFound)
+
+		err = s.Responsibles.IsUserExists(ctx, f.Outsider.Username)
+		if err != nil {
+			t.Fatalf("IsUserExists: %s", err)
+		}
+		err = s.Responsibles.IsUserExists(ctx, "no_such_user")
+		expectErr(t, "IsUserExists", err, store.ErrUserNotFound)
+
+		emp, err := s.Responsibles.GetEmployee(ctx, f.Responsible.Id)
+		if err != nil || emp.Username != f.Responsible.Username {
+			t.Fatalf("GetEmployee: unexpected employee %+v, %v", emp, err)
+		}
+		_, err = s.Responsibles.GetEmployee(ctx, unknownId)
+		expectErr(t, "GetEmployee", err, store.ErrUserNotFound)
+	})
+
+	t.Run("Responsibility", func(t *testing.T) {
+		s, f := newStores(t)
+
+		orgIds, err := s.Responsibles.ResponcibleForOrgs(ctx, f.Responsible.Id)
+		if err != nil || !slices.Equal(orgIds, []string{f.OrgId}) {
+			t.Fatalf("ResponcibleForOrgs: expected [%s], got %v, %v", f.OrgId, orgIds, err)
+		}
+		_, err = s.Responsibles.ResponcibleForOrgs(ctx, f.Outsider.Id)
+		expectErr(t, "ResponcibleForOrgs", err, store.ErrRecordNotFound)
+
+		orgIds, err = s.Responsibles.GetOrgIds(ctx, f.Competitor.Username)
+		if err != nil || !slices.Equal(orgIds, []string{f.OtherOrgId}) {
+			t.Fatalf("GetOrgIds: expected [%s], got %v, %v", f.OtherOrgId, orgIds, err)
+		}
+		_, err = s.Responsibles.GetOrgIds(ctx, "no_such_user")
+		expectErr(t, "GetOrgIds", err, store.ErrUserNotFound)
+
+		// employee may represent several organizations
+		err = s.Organizations.Grant(ctx, f.OtherOrgId, f.Responsible.Id, "viewer")
+		if err != nil {
+			t.Fatalf("Grant: %s", err)
+		}
+		orgIds, err = s.Responsibles.ResponcibleForOrgs(ctx, f.Responsible.Id)
+		expected := []string{f.OrgId, f.OtherOrgId}
+		slices.Sort(expected)
+		if err != nil || !slices.Equal(orgIds, expected) {
+			t.Fatalf("ResponcibleForOrgs: expected %v, got %v, %v", expected, orgIds, err)
+		}
+
+		err = s.Responsibles.IsResponcible(ctx, &models.Responsible{OrgId: f.OrgId, Username: f.Colleague.Username})
+		if err != nil {
+			t.Fatalf("IsResponcible: %s", err)
+		}
+		err = s.Responsibles.IsResponcible(ctx, &models.Responsible{OrgId: f.OrgId, Username: f.Competitor.Username})
+		expectErr(t, "IsResponcible", err, store.ErrRecordNotFound)
+		err = s.Responsibles.IsResponcible(ctx, &models.Responsible{OrgId: f.OrgId, Username: "no_such_user"})
+		expectErr(t, "IsResponcible", err, store.ErrUserNotFound)
+
+		respId, err := s.Responsibles.GetResponsibleUUID(ctx, &models.Responsible{OrgId: f.OrgId, Username: f.Responsible.Username})
+		if err != nil || respId == "" {
+			t.Fatalf("GetResponsibleUUID: unexpected %q, %v", respId, err)
+		}
+
+		roles, err := s.Responsibles.GetRoles(ctx, f.Responsible.Id)
+		if err != nil || len(roles) != 2 || roles[f.OrgId] != "admin" || roles[f.OtherOrgId] != "viewer" {
+			t.Fatalf("GetRoles: unexpected %v, %v", roles, err)
+		}
+		_, err = s.Responsibles.GetRoles(ctx, f.Outsider.Id)
+		expectErr(t, "GetRoles", err, store.ErrRecordNotFound)
+
+		count, err := s.Responsibles.CountResponsibles(ctx, f.OrgId, []string{"admin", "approver"})
+		if err != nil || count != 2 {
+			t.Fatalf("CountResponsibles: expected 2, got %d, %v", count, err)
+		}
+		count, err = s.Responsibles.CountResponsibles(ctx, f.OtherOrgId, []string{"approver"})
+		if err != nil || count != 0 {
+			t.Fatalf("CountResponsibles: expected 0, got %d, %v", count, err)
+		}
+		count, err = s.Responsibles.CountResponsibles(ctx, unknownId, []string{"admin"})
+		if err != nil || count != 0 {
+			t.Fatalf("CountResponsibles: expected 0, got %d, %v", count, err)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Package storetest contains conformance suite every implementation of the
+// store interfaces has to pass.
+//
+// Backend test calls Run with factory returning fresh empty stores seeded
+// with fixture records:
+//
+//	func TestMemstore(t *testing.T) {
+//		storetest.Run(t, storetest.NewMemory)
+//	}
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+// unknownId is valid uuid that never belongs to any record
+const unknownId = "00000000-0000-0000-0000-000000000000"
+
+// ctx is passed to every store call of the suite
+var ctx = context.Background()
+
+type Stores struct {
+	Tenders       store.Tenders
+	Bids          store.Bids
+	Responsibles  store.Responsibles
+	Organizations store.Organizations
+	Employees     store.Employees
+	Audit         store.Audit
+	Evaluations   store.Evaluations
+	Attachments   store.Attachments
+	Blobs         store.BlobStore
+	UnitOfWork    store.UnitOfWork
+}
+
+// Fixture describes records backend has to be seeded with before the suite run
+type Fixture struct {
+	// OrgId has Responsible and Colleague as its responsibles
+	OrgId       string
+	Responsible models.Employee
+	Colleague   models.Employee
+	// OtherOrgId has Competitor as its only responsible
+	OtherOrgId string
+	Competitor models.Employee
+	// Outsider isn't responsible for any organization
+	Outsider models.Employee
+}
+
+// Factory returns empty stores seeded with fixture, called for every test case
+type Factory func(t *testing.T) (Stores, Fixture)
+
+func Run(t *testing.T, newStores Factory) {
+	t.Run("Tenders", func(t *testing.T) {
+		runTenders(t, newStores)
+	})
+	t.Run("Bids", func(t *testing.T) {
+		runBids(t, newStores)
+	})
+	t.Run("Responsibles", func(t *testing.T) {
+		runResponsibles(t, newStores)
+	})
+	t.Run("Organizations", func(t *testing.T) {
+		runOrganizations(t, newStores)
+	})
+	t.Run("Employees", func(t *testing.T) {
+		runEmployees(t, newStores)
+	})
+	t.Run("Audit", func(t *testing.T) {
+		runAudit(t, newStores)
+	})
+	t.Run("Evaluations", func(t *testing.T) {
+		runEvaluations(t, newStores)
+	})
+	t.Run("Attachments", func(t *testing.T) {
+		runAttachments(t, newStores)
+	})
+	t.Run("UnitOfWork", func(t *testing.T) {
+		runUnitOfWork(t, newStores)
+	})
+}
+
+func createTender(t *testing.T, s Stores, f Fixture, name, servType string) *models.Tender {
+	t.Helper()
+
+	tnd, err := s.Tenders.Create(ctx, &models.Tender{
+		Name:        name,
+		Description: name + " description",
+		ServType:    servType,
+	}, &models.Responsible{
+		OrgId:    f.OrgId,
+		Username: f.Responsible.Username,
+	})
+	if err != nil {
+		t.Fatalf("Create tender: %s", err)
+	}
+	return tnd
+}
+
+func createBid(t *testing.T, s Stores, tenderId, authorType, authorId, orgId, name string) *models.Bid {
+	t.Helper()
+
+	bid, err := s.Bids.Create(ctx, &models.Bid{
+		Name:        name,
+		Description: name + " description",
+		TenderId:    tenderId,
+		AuthorType:  authorType,
+		AuthorId:    authorId,
+	}, orgId)
+	if err != nil {
+		t.Fatalf("Create bid: %s", err)
+	}
+	return bid
+}
+
+func publishBid(t *testing.T, s Stores, bidId string) *models.Bid {
+	t.Helper()
+
+	bid, err := s.Bids.GetCondition(ctx, bidId, store.Latest)
+	if err != nil {
+		t.Fatalf("GetCondition: %s", err)
+	}
+	bid.Status = "Published"
+	bid.Version += 1
+	bid, err = s.Bids.UpdateCondition(ctx, bid)
+	if err != nil {
+		t.Fatalf("UpdateCondition: %s", err)
+	}
+	return bid
+}
+
+func expectErr(t *testing.T, method string, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("%s: expected error %q, got %v", method, target, err)
+	}
+}
+
+func names[T any](items []*T, name func(*T) string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, name(item))
+	}
+	return result
+}
+
+func expectNames(t *testing.T, method string, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", method, want, got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected %v, got %v", method, want, got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func tenderName(t *models.Tender) string { return t.Name }
+
+func runTenders(t *testing.T, newStores Factory) {
+	t.Run("CreateAndGetCondition", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		if tnd.Id == "" || tnd.Version != 1 || tnd.Status != "Created" || tnd.Created.IsZero() {
+			t.Fatalf("Create: unexpected tender %+v", tnd)
+		}
+
+		got, err := s.Tenders.GetCondition(ctx, tnd.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition: %s", err)
+		}
+		if got.Name != "Roads" || got.ServType != "Construction" || got.OrgId != f.OrgId || got.Version != 1 {
+			t.Fatalf("GetCondition: unexpected tender %+v", got)
+		}
+
+		status, err := s.Tenders.GetStatus(ctx, tnd.Id)
+		if err != nil || status != "Created" {
+			t.Fatalf("GetStatus: expected Created, got %q, %v", status, err)
+		}
+
+		_, err = s.Tenders.GetCondition(ctx, unknownId, store.Latest)
+		expectErr(t, "GetCondition", err, store.ErrRecordNotFound)
+		_, err = s.Tenders.GetStatus(ctx, unknownId)
+		expectErr(t, "GetStatus", err, store.ErrRecordNotFound)
+	})
+
+	t.Run("VersionHistory", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+
+		next := *tnd
+		next.OrgId = f.OrgId
+		next.Name = "Bridges"
+		next.Status = "Published"
+		next.Version = 2
+		_, err := s.Tenders.UpdateCondition(ctx, &next)
+		if err != nil {
+			t.Fatalf("UpdateCondition: %s", err)
+		}
+
+		latest, err := s.Tenders.GetCondition(ctx, tnd.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition: %s", err)
+		}
+		if latest.Version != 2 || latest.Name != "Bridges" || latest.Status != "Published" {
+			t.Fatalf("GetCondition latest: unexpected tender %+v", latest)
+		}
+
+		first, err := s.Tenders.GetCondition(ctx, tnd.Id, 1)
+		if err != nil {
+			t.Fatalf("GetCondition: %s", err)
+		}
+		if first.Version != 1 || first.Name != "Roads" || first.Status != "Created" {
+			t.Fatalf("GetCondition 1: unexpected tender %+v", first)
+		}
+
+		_, err = s.Tenders.GetCondition(ctx, tnd.Id, 3)
+		expectErr(t, "GetCondition", err, store.ErrRecordNotFound)
+
+		version, err := s.Tenders.GetTenderLatestVersion(ctx, tnd.Id)
+		if err != nil || version != 2 {
+			t.Fatalf("GetTenderLatestVersion: expected 2, got %d, %v", version, err)
+		}
+		_, err = s.Tenders.GetTenderLatestVersion(ctx, unknownId)
+		expectErr(t, "GetTenderLatestVersion", err, store.ErrRecordNotFound)
+
+		versions, err := s.Tenders.GetVersionsList(ctx, tnd.Id, 10, 0)
+		if err != nil {
+			t.Fatalf("GetVersionsList: %s", err)
+		}
+		expectNames(t, "GetVersionsList", names(versions, tenderName), []string{"Roads", "Bridges"})
+		if versions[0].Version != 1 || versions[1].Version != 2 || versions[1].OrgId != f.OrgId {
+			t.Fatalf("GetVersionsList: unexpected versions %+v, %+v", versions[0], versions[1])
+		}
+
+		page, err := s.Tenders.GetVersionsList(ctx, tnd.Id, 1, 1)
+		if err != nil {
+			t.Fatalf("GetVersionsList: %s", err)
+		}
+		expectNames(t, "GetVersionsList page", names(page, tenderName), []string{"Bridges"})
+
+		unknown, err := s.Tenders.GetVersionsList(ctx, unknownId, 10, 0)
+		if err != nil || len(unknown) != 0 {
+			t.Fatalf("GetVersionsList: expected no versions, got %d, %v", len(unknown), err)
+		}
+
+		// existing version can't be written twice
+		_, err = s.Tenders.UpdateCondition(ctx, &next)
+		expectErr(t, "UpdateCondition", err, store.ErrRecordAlreadyExists)
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		s, f := newStores(t)
+
+		createTender(t, s, f, "C", "Delivery")
+		createTender(t, s, f, "A", "Construction")
+		createTender(t, s, f, "B", "Manufacture")
+
+		all, err := s.Tenders.GetLimitedList(ctx, 10, 0, nil)
+		if err != nil {
+			t.Fatalf("GetLimitedList: %s", err)
+		}
+		expectNames(t, "GetLimitedList", names(all, tenderName), []string{"A", "B", "C"})
+
+		page, err := s.Tenders.GetLimitedList(ctx, 1, 1, nil)
+		if err != nil {
+			t.Fatalf("GetLimitedList: %s", err)
+		}
+		expectNames(t, "GetLimitedList page", names(page, tenderName), []string{"B"})
+
+		filtered, err := s.Tenders.GetLimitedList(ctx, 10, 0, []string{"Delivery", "Construction"})
+		if err != nil {
+			t.Fatalf("GetLimitedList: %s", err)
+		}
+		expectNames(t, "GetLimitedList filtered", names(filtered, tenderName), []string{"A", "C"})
+
+		mine, err := s.Tenders.GetUserTenders(ctx, 10, 0, f.Responsible.Username)
+		if err != nil {
+			t.Fatalf("GetUserTenders: %s", err)
+		}
+		expectNames(t, "GetUserTenders", names(mine, tenderName), []string{"A", "B", "C"})
+
+		others, err := s.Tenders.GetUserTenders(ctx, 10, 0, f.Colleague.Username)
+		if err != nil {
+			t.Fatalf("GetUserTenders: %s", err)
+		}
+		expectNames(t, "GetUserTenders other user", names(others, tenderName), []string{})
+	})
+
+	t.Run("Ownership", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+
+		respUUIDs, err := s.Responsibles.GetRespUUIDs(ctx, f.Colleague.Username)
+		if err != nil {
+			t.Fatalf("GetRespUUIDs: %s", err)
+		}
+		err = s.Tenders.IsResponcibleFor(ctx, tnd.Id, respUUIDs)
+		if err != nil {
+			t.Fatalf("IsResponcibleFor: %s", err)
+		}
+
+		competitorUUIDs, err := s.Responsibles.GetRespUUIDs(ctx, f.Competitor.Username)
+		if err != nil {
+			t.Fatalf("GetRespUUIDs: %s", err)
+		}
+		err = s.Tenders.IsResponcibleFor(ctx, tnd.Id, competitorUUIDs)
+		expectErr(t, "IsResponcibleFor", err, store.ErrRecordNotFound)
+
+		bid := createBid(t, s, tnd.Id, "Organization", f.OtherOrgId, f.OtherOrgId, "Offer")
+		orgId, err := s.Tenders.GetOrgIdByBidId(ctx, bid.Id)
+		if err != nil || orgId != f.OrgId {
+			t.Fatalf("GetOrgIdByBidId: expected %s, got %s, %v", f.OrgId, orgId, err)
+		}
+		_, err = s.Tenders.GetOrgIdByBidId(ctx, unknownId)
+		expectErr(t, "GetOrgIdByBidId", err, store.ErrRecordNotFound)
+	})
+	t.Run("Deadlines", func(t *testing.T) {
+		s, f := newStores(t)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		passed := now.Add(-time.Hour)
+		upcoming := now.Add(time.Hour)
+
+		expired, err := s.Tenders.Create(ctx, &models.Tender{
+			Name:        "Expired",
+			Description: "Expired description",
+			ServType:    "Delivery",
+			Deadline:    &passed,
+		}, &models.Responsible{
+			OrgId:    f.OrgId,
+			Username: f.Responsible.Username,
+		})
+		if err != nil {
+			t.Fatalf("Create tender: %s", err)
+		}
+		got, err := s.Tenders.GetCondition(ctx, expired.Id, store.Latest)
+		if err != nil || got.Deadline == nil || !got.Deadline.Equal(passed) {
+			t.Fatalf("GetCondition: expected deadline %s, got %+v, %v", passed, got, err)
+		}
+
+		open := createTender(t, s, f, "Open", "Delivery")
+		if open.Deadline != nil {
+			t.Fatalf("Create: expected no deadline, got %s", open.Deadline)
+		}
+
+		// only published tenders are expiring
+		list, err := s.Tenders.GetExpired(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("GetExpired: %s", err)
+		}
+		expectNames(t, "GetExpired", names(list, tenderName), []string{})
+
+		got.Status = "Published"
+		got.Version = 2
+		_, err = s.Tenders.UpdateCondition(ctx, got)
+		if err != nil {
+			t.Fatalf("UpdateCondition: %s", err)
+		}
+
+		next := *open
+		next.OrgId = f.OrgId
+		next.Status = "Published"
+		next.Version = 2
+		next.Deadline = &upcoming
+		_, err = s.Tenders.UpdateCondition(ctx, &next)
+		if err != nil {
+			t.Fatalf("UpdateCondition: %s", err)
+		}
+
+		list, err = s.Tenders.GetExpired(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("GetExpired: %s", err)
+		}
+		expectNames(t, "GetExpired", names(list, tenderName), []string{"Expired"})
+		if list[0].OrgId != f.OrgId || list[0].Version != 2 {
+			t.Fatalf("GetExpired: unexpected tender %+v", list[0])
+		}
+
+		list, err = s.Tenders.GetExpired(ctx, upcoming, 10)
+		if err != nil {
+			t.Fatalf("GetExpired: %s", err)
+		}
+		expectNames(t, "GetExpired", names(list, tenderName), []string{"Expired", "Open"})
+
+		err = s.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+			locked, err := repos.Tenders.TryLock(ctx, expired.Id)
+			if err != nil || !locked {
+				t.Fatalf("TryLock: expected lock, got %t, %v", locked, err)
+			}
+			locked, err = repos.Tenders.TryLock(ctx, unknownId)
+			if err != nil || locked {
+				t.Fatalf("TryLock: expected no lock of unknown tender, got %t, %v", locked, err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do: %s", err)
+		}
+
+		counts, err := s.Tenders.CountByStatus(ctx)
+		if err != nil || len(counts) != 1 || counts["Published"] != 2 {
+			t.Fatalf("CountByStatus: expected 2 published, got %v, %v", counts, err)
+		}
+	})
+}
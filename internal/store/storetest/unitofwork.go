@@ -0,0 +1,91 @@
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+)
+
+func runUnitOfWork(t *testing.T, newStores Factory) {
+	t.Run("Commit", func(t *testing.T) {
+		s, f := newStores(t)
+
+		var tnd *models.Tender
+		var bid *models.Bid
+		err := s.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+			var err error
+			tnd, err = repos.Tenders.Create(ctx, &models.Tender{
+				Name:     "Roads",
+				ServType: "Construction",
+			}, &models.Responsible{
+				OrgId:    f.OrgId,
+				Username: f.Responsible.Username,
+			})
+			if err != nil {
+				return err
+			}
+
+			bid, err = repos.Bids.Create(ctx, &models.Bid{
+				Name:       "Offer",
+				TenderId:   tnd.Id,
+				AuthorType: "User",
+				AuthorId:   f.Competitor.Id,
+			}, f.OtherOrgId)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Do: %s", err)
+		}
+
+		_, err = s.Tenders.GetCondition(ctx, tnd.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition tender: %s", err)
+		}
+		_, err = s.Bids.GetCondition(ctx, bid.Id, store.Latest)
+		if err != nil {
+			t.Fatalf("GetCondition bid: %s", err)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		s, f := newStores(t)
+
+		tnd := createTender(t, s, f, "Roads", "Construction")
+		errAbort := errors.New("abort")
+
+		var bidId string
+		err := s.UnitOfWork.Do(ctx, func(repos store.Repositories) error {
+			bid, err := repos.Bids.Create(ctx, &models.Bid{
+				Name:       "Offer",
+				TenderId:   tnd.Id,
+				AuthorType: "User",
+				AuthorId:   f.Competitor.Id,
+			}, f.OtherOrgId)
+			if err != nil {
+				return err
+			}
+			bidId = bid.Id
+
+			next := *tnd
+			next.OrgId = f.OrgId
+			next.Status = "Closed"
+			next.Version = 2
+			_, err = repos.Tenders.UpdateCondition(ctx, &next)
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		expectErr(t, "Do", err, errAbort)
+
+		_, err = s.Bids.GetCondition(ctx, bidId, store.Latest)
+		expectErr(t, "GetCondition bid", err, store.ErrRecordNotFound)
+
+		latest, err := s.Tenders.GetCondition(ctx, tnd.Id, store.Latest)
+		if err != nil || latest.Version != 1 || latest.Status != "Created" {
+			t.Fatalf("GetCondition tender: expected untouched version 1, got %+v, %v", latest, err)
+		}
+	})
+}
@@ -1,23 +1,28 @@
 package tenderstore
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
 	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
 	"github.com/lib/pq"
 )
 
 type TenderStore struct {
-	db    *sql.DB
-	stats map[string]string
+	db       store.Querier
+	timeouts store.Timeouts
+	stats    map[string]string
 }
 
-func New(db *sql.DB) *TenderStore {
+func New(db store.Querier, timeouts store.Timeouts) *TenderStore {
 	return &TenderStore{
-		db: db,
+		db:       db,
+		timeouts: timeouts,
 		stats: map[string]string{
 			"CREATED":   "Created",
 			"PUBLISHED": "Published",
@@ -26,12 +31,15 @@ func New(db *sql.DB) *TenderStore {
 	}
 }
 
-func (t *TenderStore) GetLimitedList(limit, offset int64, servType []string) ([]*models.Tender, error) {
+func (t *TenderStore) GetLimitedList(ctx context.Context, limit, offset int64, servType []string) ([]*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var err error
 	var rows *sql.Rows
 	if len(servType) != 0 {
-		rows, err = t.db.Query(
-			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at "+
+		rows, err = t.db.QueryContext(ctx,
+			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at, tv.deadline "+
 				"FROM tenders_versions AS tv "+
 				"INNER JOIN ( "+
 				"SELECT tender_id, MAX(version) AS latest_version "+
@@ -48,8 +56,8 @@ func (t *TenderStore) GetLimitedList(limit, offset int64, servType []string) ([]
 			offset,
 		)
 	} else {
-		rows, err = t.db.Query(
-			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at "+
+		rows, err = t.db.QueryContext(ctx,
+			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at, tv.deadline "+
 				"FROM tenders_versions AS tv "+
 				"INNER JOIN ( "+
 				"SELECT tender_id, MAX(version) AS latest_version "+
@@ -68,59 +76,58 @@ func (t *TenderStore) GetLimitedList(limit, offset int64, servType []string) ([]
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
+	defer rows.Close()
 
 	result := []*models.Tender{}
 	for rows.Next() {
 		var tender models.Tender
-		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.Version, &tender.Created)
+		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.Version, &tender.Created, &tender.Deadline)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		tender.Status = t.stats[tender.Status]
 		result = append(result, &tender)
 	}
-	return result, nil
+	return result, pgerr.Translate(rows.Err())
 }
 
-func (t *TenderStore) Create(tnd *models.Tender, resp *models.Responsible) (*models.Tender, error) {
-	err := t.db.QueryRow(
+func (t *TenderStore) Create(ctx context.Context, tnd *models.Tender, resp *models.Responsible) (*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	err := t.db.QueryRowContext(ctx,
 		"INSERT INTO tenders (organization_id, username) VALUES ($1, $2) RETURNING id;",
 		resp.OrgId,
 		resp.Username,
 	).Scan(&tnd.Id)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 
-	err = t.db.QueryRow(
-		"INSERT INTO tenders_versions (tender_id, name, description, status, type) VALUES ($1, $2, $3, 'CREATED', $4) RETURNING created_at, version, status;",
+	err = t.db.QueryRowContext(ctx,
+		"INSERT INTO tenders_versions (tender_id, name, description, status, type, deadline) VALUES ($1, $2, $3, 'CREATED', $4, $5) RETURNING created_at, version, status;",
 		tnd.Id,
 		tnd.Name,
 		tnd.Description,
 		tnd.ServType,
+		tnd.Deadline,
 	).Scan(&tnd.Created, &tnd.Version, &tnd.Status)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	tnd.Status = t.stats[tnd.Status]
 
 	return tnd, nil
 }
 
-func (t *TenderStore) GetUserTenders(limit, offset int64, username string) ([]*models.Tender, error) {
-	rows, err := t.db.Query(
-		"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at "+
+func (t *TenderStore) GetUserTenders(ctx context.Context, limit, offset int64, username string) ([]*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx,
+		"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, tv.version, tv.created_at, tv.deadline "+
 			"FROM tenders AS t "+
 			"INNER JOIN tenders_versions AS tv "+
 			"ON t.id = tv.tender_id "+
@@ -139,28 +146,29 @@ func (t *TenderStore) GetUserTenders(limit, offset int64, username string) ([]*m
 		offset,
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
+	defer rows.Close()
 
 	result := []*models.Tender{}
 	for rows.Next() {
 		var tender models.Tender
-		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.Version, &tender.Created)
+		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.Version, &tender.Created, &tender.Deadline)
 		if err != nil {
-			return nil, err
+			return nil, pgerr.Translate(err)
 		}
 		tender.Status = t.stats[tender.Status]
 		result = append(result, &tender)
 	}
-	return result, nil
+	return result, pgerr.Translate(rows.Err())
 }
 
-func (t *TenderStore) GetStatus(tenderId string) (string, error) {
+func (t *TenderStore) GetStatus(ctx context.Context, tenderId string) (string, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var status string
-	err := t.db.QueryRow(
+	err := t.db.QueryRowContext(ctx,
 		"SELECT tv.status "+
 			"FROM tenders_versions AS tv "+
 			"JOIN ( "+
@@ -176,67 +184,63 @@ func (t *TenderStore) GetStatus(tenderId string) (string, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return "", store.ErrConnClosed
-		}
-		return "", err
+		return "", pgerr.Translate(err)
 	}
 	return t.stats[status], nil
 }
 
-func (t *TenderStore) IsResponcibleFor(tenderId string, respUUIDs []string) error {
+func (t *TenderStore) IsResponcibleFor(ctx context.Context, tenderId string, respUUIDs []string) error {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	if len(respUUIDs) == 0 {
 		return store.ErrRecordNotFound
 	}
-	err := t.db.QueryRow(
-		"SELECT id FROM tenders WHERE id = $1 AND responsible_id = ANY($2)",
+	var id string
+	err := t.db.QueryRowContext(ctx,
+		"SELECT t.id "+
+			"FROM tenders AS t "+
+			"INNER JOIN organization_responsible AS r ON r.organization_id = t.organization_id "+
+			"WHERE t.id = $1 AND r.id = ANY($2) "+
+			"LIMIT 1;",
 		tenderId,
 		pq.Array(respUUIDs),
-	).Err()
+	).Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return store.ErrConnClosed
-		}
-		return err
+		return pgerr.Translate(err)
 	}
 	return nil
 }
 
-func (t *TenderStore) GetTenderLatestVersion(tenderId, username string) (int64, error) {
+func (t *TenderStore) GetTenderLatestVersion(ctx context.Context, tenderId string) (int64, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var version int64
-	err := t.db.QueryRow(
-		"SELECT tv.version "+
-			"FROM tenders AS t "+
-			"INNER JOIN tenders_versions tv ON t.id = tv.tender_id "+
-			"JOIN ( "+
-			"SELECT tender_id, MAX(version) AS latest_version "+
-			"FROM tenders_versions "+
-			"GROUP BY tender_id"+
-			") lv ON tv.tender_id = lv.tender_id AND tv.version = lv.latest_version "+
-			"WHERE t.id = $1 AND t.username = $2;",
+	err := t.db.QueryRowContext(ctx,
+		"SELECT version FROM tenders_versions WHERE tender_id = $1 ORDER BY version DESC LIMIT 1;",
 		tenderId,
-		username,
 	).Scan(&version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return -1, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return -1, store.ErrConnClosed
-		}
-		return -1, err
+		return -1, pgerr.Translate(err)
 	}
 	return version, nil
 }
-func (t *TenderStore) GetCondition(tenderId string, version int64) (*models.Tender, error) {
+func (t *TenderStore) GetCondition(ctx context.Context, tenderId string, version int64) (*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var tnd models.Tender
 	var err error
 	if version == store.Latest {
-		err = t.db.QueryRow(
-			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at "+
+		err = t.db.QueryRowContext(ctx,
+			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at, tv.deadline "+
 				"FROM tenders AS t "+
 				"INNER JOIN tenders_versions tv ON t.id = tv.tender_id "+
 				"JOIN ( "+
@@ -246,36 +250,124 @@ func (t *TenderStore) GetCondition(tenderId string, version int64) (*models.Tend
 				") lv ON tv.tender_id = lv.tender_id AND tv.version = lv.latest_version "+
 				"WHERE t.id = $1;",
 			tenderId,
-		).Scan(&tnd.Id, &tnd.Name, &tnd.Description, &tnd.Status, &tnd.ServType, &tnd.OrgId, &tnd.Version, &tnd.Created)
+		).Scan(&tnd.Id, &tnd.Name, &tnd.Description, &tnd.Status, &tnd.ServType, &tnd.OrgId, &tnd.Version, &tnd.Created, &tnd.Deadline)
 	} else {
-		err = t.db.QueryRow(
-			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at "+
+		err = t.db.QueryRowContext(ctx,
+			"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at, tv.deadline "+
 				"FROM tenders AS t "+
 				"INNER JOIN tenders_versions tv ON t.id = tv.tender_id "+
 				"WHERE t.id = $1 AND tv.version = $2;",
 			tenderId,
 			version,
-		).Scan(&tnd.Id, &tnd.Name, &tnd.Description, &tnd.Status, &tnd.ServType, &tnd.OrgId, &tnd.Version, &tnd.Created)
+		).Scan(&tnd.Id, &tnd.Name, &tnd.Description, &tnd.Status, &tnd.ServType, &tnd.OrgId, &tnd.Version, &tnd.Created, &tnd.Deadline)
 	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	tnd.Status = t.stats[tnd.Status]
 	return &tnd, nil
 }
 
+func (t *TenderStore) GetVersionsList(ctx context.Context, tenderId string, limit, offset int64) ([]*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx,
+		"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at, tv.deadline "+
+			"FROM tenders AS t "+
+			"INNER JOIN tenders_versions tv ON t.id = tv.tender_id "+
+			"WHERE t.id = $1 "+
+			"ORDER BY tv.version ASC "+
+			"LIMIT $2 "+
+			"OFFSET $3;",
+		tenderId,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Tender{}
+	for rows.Next() {
+		var tender models.Tender
+		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.OrgId, &tender.Version, &tender.Created, &tender.Deadline)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		tender.Status = t.stats[tender.Status]
+		result = append(result, &tender)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+// GetExpired lists published tenders with passed deadline as candidates for closing,
+// nothing is locked: caller takes each tender with TryLock and rereads it under the lock
+func (t *TenderStore) GetExpired(ctx context.Context, now time.Time, limit int64) ([]*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx,
+		"SELECT tv.tender_id, tv.name, tv.description, tv.status, tv.type, t.organization_id, tv.version, tv.created_at, tv.deadline "+
+			"FROM tenders AS t "+
+			"INNER JOIN tenders_versions tv ON t.id = tv.tender_id "+
+			"WHERE tv.version = (SELECT MAX(version) FROM tenders_versions WHERE tender_id = t.id) "+
+			"AND tv.status = 'PUBLISHED' AND tv.deadline <= $1 "+
+			"ORDER BY tv.deadline ASC "+
+			"LIMIT $2;",
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := []*models.Tender{}
+	for rows.Next() {
+		var tender models.Tender
+		err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServType, &tender.OrgId, &tender.Version, &tender.Created, &tender.Deadline)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		tender.Status = t.stats[tender.Status]
+		result = append(result, &tender)
+	}
+	return result, pgerr.Translate(rows.Err())
+}
+
+// TryLock takes row lock of tender held until end of transaction, outside of transaction it's released at once
+func (t *TenderStore) TryLock(ctx context.Context, tenderId string) (bool, error) {
+	ctx, cancel := t.timeouts.ForWrite(ctx)
+	defer cancel()
+
+	var id string
+	err := t.db.QueryRowContext(ctx,
+		"SELECT id FROM tenders WHERE id = $1 FOR UPDATE SKIP LOCKED;",
+		tenderId,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, pgerr.Translate(err)
+	}
+	return true, nil
+}
+
 // GetResponsible(tenderId string) (*models.Responsible, error)
-func (t *TenderStore) UpdateCondition(newCondition *models.Tender) (*models.Tender, error) {
+func (t *TenderStore) UpdateCondition(ctx context.Context, newCondition *models.Tender) (*models.Tender, error) {
+	ctx, cancel := t.timeouts.ForWrite(ctx)
+	defer cancel()
+
 	newCondition.Status = strings.ToUpper(newCondition.Status)
 
-	_, err := t.db.Exec(
-		"INSERT INTO tenders_versions (tender_id, name, description, status, type, version, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+	_, err := t.db.ExecContext(ctx,
+		"INSERT INTO tenders_versions (tender_id, name, description, status, type, version, created_at, deadline) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
 		newCondition.Id,
 		newCondition.Name,
 		newCondition.Description,
@@ -283,20 +375,21 @@ func (t *TenderStore) UpdateCondition(newCondition *models.Tender) (*models.Tend
 		newCondition.ServType,
 		newCondition.Version,
 		newCondition.Created,
+		newCondition.Deadline,
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			return nil, store.ErrConnClosed
-		}
-		return nil, err
+		return nil, pgerr.Translate(err)
 	}
 	newCondition.Status = t.stats[newCondition.Status]
 	return newCondition, nil
 }
 
-func (t *TenderStore) GetOrgIdByBidId(bidId string) (string, error) {
+func (t *TenderStore) GetOrgIdByBidId(ctx context.Context, bidId string) (string, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
 	var orgId string
-	err := t.db.QueryRow(
+	err := t.db.QueryRowContext(ctx,
 		"SELECT t.organization_id "+
 			"FROM bids AS b "+
 			"INNER JOIN tenders t ON b.tender_id = t.id "+
@@ -307,10 +400,39 @@ func (t *TenderStore) GetOrgIdByBidId(bidId string) (string, error) {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", store.ErrRecordNotFound
 		}
-		if strings.Contains(err.Error(), "no such host") {
-			return "", store.ErrConnClosed
-		}
-		return "", err
+		return "", pgerr.Translate(err)
 	}
 	return orgId, nil
 }
+
+func (t *TenderStore) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	ctx, cancel := t.timeouts.ForRead(ctx)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx,
+		"SELECT tv.status, COUNT(*) "+
+			"FROM tenders_versions AS tv "+
+			"INNER JOIN ( "+
+			"SELECT tender_id, MAX(version) AS latest_version "+
+			"FROM tenders_versions "+
+			"GROUP BY tender_id "+
+			") AS lv ON tv.tender_id = lv.tender_id AND tv.version = lv.latest_version "+
+			"GROUP BY tv.status;",
+	)
+	if err != nil {
+		return nil, pgerr.Translate(err)
+	}
+	defer rows.Close()
+
+	result := map[string]int64{}
+	for rows.Next() {
+		var status string
+		var count int64
+		err = rows.Scan(&status, &count)
+		if err != nil {
+			return nil, pgerr.Translate(err)
+		}
+		result[t.stats[status]] = count
+	}
+	return result, pgerr.Translate(rows.Err())
+}
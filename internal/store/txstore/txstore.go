@@ -0,0 +1,70 @@
+package txstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/attachmentstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/auditstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/bidstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/evaluationstore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/pgerr"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/responsiblestore"
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/tenderstore"
+)
+
+// UnitOfWork runs sql stores inside one postgres transaction
+type UnitOfWork struct {
+	db       *sql.DB
+	timeouts store.Timeouts
+	wrap     func(store.Querier) store.Querier
+}
+
+// New creates unit of work, wrap decorates transaction given to stores, nil leaves it as is
+func New(db *sql.DB, timeouts store.Timeouts, wrap func(store.Querier) store.Querier) *UnitOfWork {
+	return &UnitOfWork{
+		db:       db,
+		timeouts: timeouts,
+		wrap:     wrap,
+	}
+}
+
+// Do rolls transaction back when ctx is done before commit
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos store.Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		err = pgerr.Translate(err)
+		// waiting for connection may time out or be cancelled by caller
+		if errors.Is(err, store.ErrConnClosed) || errors.Is(err, store.ErrTimeout) || errors.Is(err, context.Canceled) {
+			return err
+		}
+		return fmt.Errorf("%w: %s", store.ErrStartingTransaction, err)
+	}
+
+	var q store.Querier = tx
+	if u.wrap != nil {
+		q = u.wrap(tx)
+	}
+
+	err = fn(store.Repositories{
+		Tenders:      tenderstore.New(q, u.timeouts),
+		Bids:         bidstore.New(q, u.timeouts),
+		Responsibles: responsiblestore.New(q, u.timeouts),
+		Audit:        auditstore.New(q, u.timeouts),
+		Evaluations:  evaluationstore.New(q, u.timeouts),
+		Attachments:  attachmentstore.New(q, u.timeouts),
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit: %w", pgerr.Translate(err))
+	}
+	return nil
+}
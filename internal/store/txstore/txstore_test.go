@@ -0,0 +1,12 @@
+package txstore_test
+
+import (
+	"testing"
+
+	"github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/store/storetest"
+)
+
+// TestPostgres runs on database from storetest.PostgresEnv
+func TestPostgres(t *testing.T) {
+	storetest.Run(t, storetest.NewPostgres)
+}
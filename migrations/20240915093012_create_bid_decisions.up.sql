@@ -0,0 +1,18 @@
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'APPROVED';
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'REJECTED';
+
+CREATE TYPE bid_decision AS ENUM (
+    'Approved',
+    'Rejected'
+);
+
+CREATE TABLE bid_decisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+
+    decision bid_decision NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_bid_decision UNIQUE (bid_id, user_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_log;
+DROP TYPE IF EXISTS audit_entity;
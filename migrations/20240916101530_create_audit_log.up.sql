@@ -0,0 +1,22 @@
+CREATE TYPE audit_entity AS ENUM (
+    'Tender',
+    'Bid'
+);
+
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+
+    action VARCHAR(50) NOT NULL,
+    entity_type audit_entity NOT NULL,
+    entity_id UUID NOT NULL,
+    old_version INT,
+    new_version INT,
+
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    -- clock_timestamp keeps order of entries written in one transaction
+    created_at TIMESTAMP DEFAULT clock_timestamp()
+);
+
+CREATE INDEX audit_log_organization_idx ON audit_log (organization_id, created_at DESC);
@@ -0,0 +1,6 @@
+DELETE FROM audit_log WHERE actor_id IS NULL;
+ALTER TABLE audit_log ALTER COLUMN actor_id SET NOT NULL;
+
+DROP INDEX IF EXISTS tenders_versions_deadline_idx;
+
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS deadline;
@@ -0,0 +1,7 @@
+ALTER TABLE tenders_versions ADD COLUMN deadline TIMESTAMP WITH TIME ZONE;
+
+-- used by scheduler looking for expired published tenders
+CREATE INDEX tenders_versions_deadline_idx ON tenders_versions (deadline) WHERE status = 'PUBLISHED';
+
+-- tenders closed by scheduler are recorded without actor
+ALTER TABLE audit_log ALTER COLUMN actor_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE bids_versions
+    DROP CONSTRAINT IF EXISTS bid_price_currency,
+    DROP COLUMN IF EXISTS delivery_days,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS amount;
@@ -0,0 +1,5 @@
+ALTER TABLE bids_versions
+    ADD COLUMN amount NUMERIC(15, 2) CHECK (amount > 0),
+    ADD COLUMN currency CHAR(3),
+    ADD COLUMN delivery_days INTEGER CHECK (delivery_days >= 0),
+    ADD CONSTRAINT bid_price_currency CHECK ((amount IS NULL) = (currency IS NULL));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bid_scores;
+DROP TABLE IF EXISTS tender_criteria;
@@ -0,0 +1,22 @@
+CREATE TABLE tender_criteria (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+
+    name VARCHAR(100) NOT NULL,
+    weight INTEGER NOT NULL CHECK (weight BETWEEN 1 AND 100),
+    position INTEGER NOT NULL,
+
+    CONSTRAINT unique_tender_criterion UNIQUE (tender_id, name)
+);
+
+CREATE TABLE bid_scores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES tender_criteria(id) ON DELETE CASCADE,
+    evaluator_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+
+    value INTEGER NOT NULL CHECK (value BETWEEN 0 AND 100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_bid_score UNIQUE (bid_id, criterion_id, evaluator_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS attachments;
+DROP TYPE IF EXISTS attachment_entity;
@@ -0,0 +1,21 @@
+CREATE TYPE attachment_entity AS ENUM (
+    'Tender',
+    'Bid'
+);
+
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entity_type attachment_entity NOT NULL,
+    entity_id UUID NOT NULL,
+    author_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    -- hex encoded sha256 of content, blob store keeps one copy per digest
+    digest CHAR(64) NOT NULL,
+
+    created_at TIMESTAMP DEFAULT clock_timestamp()
+);
+
+CREATE INDEX attachments_entity_idx ON attachments (entity_type, entity_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE organization_responsible DROP COLUMN IF EXISTS role;
+
+DROP TYPE IF EXISTS responsible_role;
@@ -0,0 +1,9 @@
+CREATE TYPE responsible_role AS ENUM (
+    'viewer',
+    'editor',
+    'approver',
+    'admin'
+);
+
+-- existing responsibles keep full access to their organizations
+ALTER TABLE organization_responsible ADD COLUMN role responsible_role NOT NULL DEFAULT 'admin';
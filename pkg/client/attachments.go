@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// File is downloaded attachment, caller closes Body
+type File struct {
+	Name        string
+	ContentType string
+	Size        int64
+	// Digest is hex sha256 of content
+	Digest string
+	Body   io.ReadCloser
+}
+
+func (c *Client) AddTenderAttachment(ctx context.Context, tenderId, name, contentType string, content io.Reader) (*Attachment, error) {
+	return c.addAttachment(ctx, "/tenders/"+escape(tenderId)+"/attachments", name, contentType, content)
+}
+
+func (c *Client) ListTenderAttachments(ctx context.Context, tenderId string) ([]*Attachment, error) {
+	return c.listAttachments(ctx, "/tenders/"+escape(tenderId)+"/attachments")
+}
+
+func (c *Client) DownloadTenderAttachment(ctx context.Context, tenderId, attachmentId string) (*File, error) {
+	return c.download(ctx, "/tenders/"+escape(tenderId)+"/attachments/"+escape(attachmentId))
+}
+
+func (c *Client) AddBidAttachment(ctx context.Context, bidId, name, contentType string, content io.Reader) (*Attachment, error) {
+	return c.addAttachment(ctx, "/bids/"+escape(bidId)+"/attachments", name, contentType, content)
+}
+
+func (c *Client) ListBidAttachments(ctx context.Context, bidId string) ([]*Attachment, error) {
+	return c.listAttachments(ctx, "/bids/"+escape(bidId)+"/attachments")
+}
+
+func (c *Client) DownloadBidAttachment(ctx context.Context, bidId, attachmentId string) (*File, error) {
+	return c.download(ctx, "/bids/"+escape(bidId)+"/attachments/"+escape(attachmentId))
+}
+
+// addAttachment uploads content as file part of multipart form, the form is buffered
+// so upload can be retried
+func (c *Client) addAttachment(ctx context.Context, path, name, contentType string, content io.Reader) (*Attachment, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": name}))
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := mw.CreatePart(header)
+	if err == nil {
+		_, err = io.Copy(part, content)
+	}
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	att := &Attachment{}
+	err = c.do(ctx, call{
+		method:      http.MethodPost,
+		path:        path,
+		body:        buf.Bytes(),
+		contentType: mw.FormDataContentType(),
+	}, att)
+	if err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+func (c *Client) listAttachments(ctx context.Context, path string) ([]*Attachment, error) {
+	var atts []*Attachment
+	err := c.do(ctx, call{method: http.MethodGet, path: path}, &atts)
+	return atts, err
+}
+
+func (c *Client) download(ctx context.Context, path string) (*File, error) {
+	resp, err := c.send(ctx, call{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+		Digest:      strings.Trim(resp.Header.Get("ETag"), `"`),
+		Body:        resp.Body,
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		file.Name = params["filename"]
+	}
+	if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		file.Size = size
+	}
+	return file, nil
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Ping checks the api serves requests
+func (c *Client) Ping(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodGet, path: "/ping"}, nil)
+}
+
+// Token is bearer token of employee
+type Token struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IssueToken issues token of employee with username, issuerKey is key of token issuing
+// configured on the api. Use Client.WithToken to act as the employee.
+func (c *Client) IssueToken(ctx context.Context, username, issuerKey string) (*Token, error) {
+	cl, err := jsonCall(http.MethodPost, "/auth/token", map[string]string{"username": username})
+	if err != nil {
+		return nil, err
+	}
+	if issuerKey != "" {
+		cl.header = http.Header{}
+		cl.header.Set("X-Issuer-Key", issuerKey)
+	}
+
+	token := &Token{}
+	err = c.do(ctx, cl, token)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// NewBid is bid to create, Amount and Currency are set together
+type NewBid struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	TenderId     string   `json:"tenderId"`
+	AuthorType   string   `json:"authorType"`
+	AuthorId     string   `json:"authorId"`
+	Amount       *float64 `json:"amount,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
+	DeliveryDays *int64   `json:"deliveryDays,omitempty"`
+}
+
+// BidEdit holds changed fields of bid, empty ones are kept
+type BidEdit struct {
+	Name         string   `json:"name,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Amount       *float64 `json:"amount,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
+	DeliveryDays *int64   `json:"deliveryDays,omitempty"`
+}
+
+func (c *Client) CreateBid(ctx context.Context, bid NewBid) (*Bid, error) {
+	cl, err := jsonCall(http.MethodPost, "/bids/new", bid)
+	if err != nil {
+		return nil, err
+	}
+	return c.bid(ctx, cl)
+}
+
+// ListMyBids lists bids authored by the employee
+func (c *Client) ListMyBids(ctx context.Context, page Page) ([]*Bid, error) {
+	var bids []*Bid
+	err := c.do(ctx, call{method: http.MethodGet, path: "/bids/my", query: page.query()}, &bids)
+	return bids, err
+}
+
+// ListTenderBids lists bids of tender in order, one of BidsBy* orders
+func (c *Client) ListTenderBids(ctx context.Context, tenderId string, page Page, order string) ([]*Bid, error) {
+	cl := call{method: http.MethodGet, path: "/bids/" + escape(tenderId) + "/list", query: page.query()}
+	if order != BidsByName {
+		cl.query.Set("sort", order)
+	}
+
+	var bids []*Bid
+	err := c.do(ctx, cl, &bids)
+	return bids, err
+}
+
+// GetTenderRanking ranks bids of tender by price or score, one of RankBy* orders
+func (c *Client) GetTenderRanking(ctx context.Context, tenderId, by string) ([]*RankedBid, error) {
+	cl := call{method: http.MethodGet, path: "/tenders/" + escape(tenderId) + "/ranking", query: url.Values{}}
+	if by != "" {
+		cl.query.Set("by", by)
+	}
+
+	var bids []*RankedBid
+	err := c.do(ctx, cl, &bids)
+	return bids, err
+}
+
+func (c *Client) GetBidStatus(ctx context.Context, bidId string) (string, error) {
+	var status string
+	err := c.do(ctx, call{method: http.MethodGet, path: "/bids/" + escape(bidId) + "/status"}, &status)
+	return status, err
+}
+
+// UpdateBidStatus changes status of bid of expected version, see AnyVersion
+func (c *Client) UpdateBidStatus(ctx context.Context, bidId, status string, version int64) (*Bid, error) {
+	cl := call{
+		method: http.MethodPut,
+		path:   "/bids/" + escape(bidId) + "/status",
+		query:  url.Values{"status": {status}},
+	}
+	return c.bid(ctx, ifMatch(cl, version))
+}
+
+// EditBid changes bid of expected version, see AnyVersion
+func (c *Client) EditBid(ctx context.Context, bidId string, edit BidEdit, version int64) (*Bid, error) {
+	cl, err := jsonCall(http.MethodPatch, "/bids/"+escape(bidId)+"/edit", edit)
+	if err != nil {
+		return nil, err
+	}
+	return c.bid(ctx, ifMatch(cl, version))
+}
+
+// SubmitDecision approves or rejects bid, one of Decision* decisions
+func (c *Client) SubmitDecision(ctx context.Context, bidId, decision string) (*Bid, error) {
+	return c.bid(ctx, call{
+		method: http.MethodPut,
+		path:   "/bids/" + escape(bidId) + "/submit_decision",
+		query:  url.Values{"decision": {decision}},
+	})
+}
+
+func (c *Client) SubmitFeedback(ctx context.Context, bidId, feedback string) (*Bid, error) {
+	return c.bid(ctx, call{
+		method: http.MethodPut,
+		path:   "/bids/" + escape(bidId) + "/feedback",
+		query:  url.Values{"bidFeedback": {feedback}},
+	})
+}
+
+// RollbackBid makes new version of bid with fields of version
+func (c *Client) RollbackBid(ctx context.Context, bidId string, version int64) (*Bid, error) {
+	path := "/bids/" + escape(bidId) + "/rollback/" + strconv.FormatInt(version, 10)
+	return c.bid(ctx, call{method: http.MethodPut, path: path})
+}
+
+// ListBidReviews lists past feedback on bids of author, it's available to responsibles
+// of tender the author bids on
+func (c *Client) ListBidReviews(ctx context.Context, tenderId, authorUsername string, page Page) ([]*Feedback, error) {
+	cl := call{method: http.MethodGet, path: "/bids/" + escape(tenderId) + "/reviews", query: page.query()}
+	cl.query.Set("authorUsername", authorUsername)
+
+	var reviews []*Feedback
+	err := c.do(ctx, cl, &reviews)
+	return reviews, err
+}
+
+func (c *Client) ListBidVersions(ctx context.Context, bidId string, page Page) ([]*Bid, error) {
+	var bids []*Bid
+	err := c.do(ctx, call{method: http.MethodGet, path: "/bids/" + escape(bidId) + "/versions", query: page.query()}, &bids)
+	return bids, err
+}
+
+func (c *Client) DiffBidVersions(ctx context.Context, bidId string, from, to int64) (*VersionsDiff, error) {
+	path := "/bids/" + escape(bidId) + "/versions/" + strconv.FormatInt(from, 10) + "/diff/" + strconv.FormatInt(to, 10)
+	diff := &VersionsDiff{}
+	err := c.do(ctx, call{method: http.MethodGet, path: path}, diff)
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func (c *Client) bid(ctx context.Context, cl call) (*Bid, error) {
+	bid := &Bid{}
+	err := c.do(ctx, cl, bid)
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
@@ -0,0 +1,236 @@
+// Package client is typed client of the tender api described by openapi.yml
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Options configures client. Requests failed by network errors or timed out are retried only
+// when they are idempotent, ones rejected by rate limit or unavailable service are retried
+// always as the api rejects them before handling. Waits between attempts double from MinBackoff up to MaxBackoff,
+// Retry-After header of response takes precedence.
+type Options struct {
+	// HTTPClient sends requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+	// Token is bearer token of acting employee, see Client.IssueToken
+	Token string
+	// Retries is number of repeated attempts, zero disables retries
+	Retries int
+	// MinBackoff and MaxBackoff default to 100ms and 5s
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client calls the api on behalf of single employee, it is safe for concurrent use
+type Client struct {
+	baseURL string
+	opts    Options
+	// orgId is organization the employee acts on behalf of, empty one lets the api choose
+	orgId string
+}
+
+// New creates client of the api served at baseURL, it includes /api prefix,
+// e.g. http://localhost:8080/api
+func New(baseURL string, opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaultMaxBackoff, opts.MinBackoff)
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		opts:    opts,
+	}
+}
+
+// WithToken returns copy of client authenticated by token
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.opts.Token = token
+	return &clone
+}
+
+// OnBehalf returns copy of client acting on behalf of organization,
+// employees responsible for several organizations choose one of them this way
+func (c *Client) OnBehalf(orgId string) *Client {
+	clone := *c
+	clone.orgId = orgId
+	return &clone
+}
+
+// call is single api operation
+type call struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	contentType string
+}
+
+// jsonCall prepares call with body encoded as json, nil body is omitted
+func jsonCall(method, path string, body interface{}) (call, error) {
+	c := call{method: method, path: path}
+	if body == nil {
+		return c, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return c, fmt.Errorf("encode request: %w", err)
+	}
+	c.body = data
+	c.contentType = "application/json"
+	return c, nil
+}
+
+// do performs call and decodes json response into out, nil out discards response
+func (c *Client) do(ctx context.Context, cl call, out interface{}) error {
+	resp, err := c.send(ctx, cl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// send performs call with retries, returned response has successful status
+// and its body must be closed
+func (c *Client) send(ctx context.Context, cl call) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, cl)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+		if err == nil {
+			err = decodeError(resp)
+		}
+		if attempt >= c.opts.Retries || ctx.Err() != nil || !retryable(cl.method, err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, cl call) (*http.Response, error) {
+	query := url.Values{}
+	for key, values := range cl.query {
+		query[key] = values
+	}
+	if c.orgId != "" {
+		query.Set("organizationId", c.orgId)
+	}
+	target := c.baseURL + cl.path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if cl.body != nil {
+		body = bytes.NewReader(cl.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for key, values := range cl.header {
+		req.Header[key] = values
+	}
+	if cl.contentType != "" {
+		req.Header.Set("Content-Type", cl.contentType)
+	}
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	return c.opts.HTTPClient.Do(req)
+}
+
+// retryable reports whether call failed by err may be repeated
+func retryable(method string, err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		// timed out request might have been applied, like one failed by network error
+		if apiErr.Status == http.StatusGatewayTimeout {
+			return idempotent(method)
+		}
+		return apiErr.Status == http.StatusTooManyRequests || apiErr.Status == http.StatusServiceUnavailable
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return idempotent(method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns wait before next attempt, the api tells it for rate limited requests
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	wait := c.opts.MinBackoff
+	for i := 0; i < attempt && wait < c.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, c.opts.MaxBackoff)
+}
+
+// escape makes id safe path segment
+func escape(id string) string {
+	return url.PathEscape(id)
+}
+
+// ifMatch sets If-Match header requiring version unless it's AnyVersion
+func ifMatch(cl call, version int64) call {
+	if version == AnyVersion {
+		return cl
+	}
+	cl.header = http.Header{}
+	cl.header.Set("If-Match", strconv.Quote(strconv.FormatInt(version, 10)))
+	return cl
+}
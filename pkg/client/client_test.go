@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flaky fails first failures attempts with fail before answering ok
+func flaky(t *testing.T, failures int32, fail func(w http.ResponseWriter)) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	attempts := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			fail(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`"Published"`))
+			return
+		}
+		w.Write([]byte(`{"id": "bid"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return New(srv.URL+"/api/", Options{Retries: 2, MinBackoff: time.Millisecond}), attempts
+}
+
+func unavailable(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte(`{"status": 503, "code": "service_unavailable", "detail": "service is unavailable"}`))
+}
+
+func dropped(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	c, attempts := flaky(t, 2, unavailable)
+	status, err := c.GetTenderStatus(ctx, "tender")
+	if err != nil || status != TenderPublished || attempts.Load() != 3 {
+		t.Fatalf("GetTenderStatus: expected success on 3rd attempt, got %s, %v after %d attempts", status, err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 3, unavailable)
+	_, err = c.GetTenderStatus(ctx, "tender")
+	if !errors.Is(err, ErrUnavailable) || attempts.Load() != 3 {
+		t.Fatalf("GetTenderStatus: expected unavailable after 3 attempts, got %v after %d attempts", err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 1, unavailable)
+	_, err = c.CreateBid(ctx, NewBid{Name: "bid"})
+	if err != nil || attempts.Load() != 2 {
+		t.Fatalf("CreateBid: expected unhandled request retried, got %v after %d attempts", err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 1, dropped)
+	_, err = c.GetTenderStatus(ctx, "tender")
+	if err != nil || attempts.Load() != 2 {
+		t.Fatalf("GetTenderStatus: expected idempotent request retried on network error, got %v after %d attempts", err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 1, dropped)
+	_, err = c.CreateBid(ctx, NewBid{Name: "bid"})
+	if err == nil || attempts.Load() != 1 {
+		t.Fatalf("CreateBid: expected request not retried on network error, got %v after %d attempts", err, attempts.Load())
+	}
+
+	timeout := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusGatewayTimeout)
+		w.Write([]byte(`{"status": 504, "code": "timeout", "detail": "service database timed out"}`))
+	}
+
+	c, attempts = flaky(t, 1, timeout)
+	_, err = c.GetTenderStatus(ctx, "tender")
+	if err != nil || attempts.Load() != 2 {
+		t.Fatalf("GetTenderStatus: expected idempotent request retried on timeout, got %v after %d attempts", err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 1, timeout)
+	_, err = c.CreateBid(ctx, NewBid{Name: "bid"})
+	if !errors.Is(err, ErrTimeout) || attempts.Load() != 1 {
+		t.Fatalf("CreateBid: expected request not retried on timeout, got %v after %d attempts", err, attempts.Load())
+	}
+
+	c, attempts = flaky(t, 1, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	_, err = c.GetTenderStatus(ctx, "tender")
+	if !errors.Is(err, ErrConflict) || attempts.Load() != 1 {
+		t.Fatalf("GetTenderStatus: expected conflict not retried, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/problem+json")
+	rec.Header().Set("Retry-After", "3")
+	rec.WriteHeader(http.StatusTooManyRequests)
+	rec.Write([]byte(`{"status": 429, "code": "too_many_requests", "detail": "rate limit exceeded", "errors": [{"field": "limit", "message": "too large"}]}`))
+
+	err := decodeError(rec.Result())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("expected too many requests error, got %v", err)
+	}
+	if apiErr.Code != CodeTooManyRequests || apiErr.RetryAfter != 3*time.Second || len(apiErr.Fields) != 1 {
+		t.Fatalf("unexpected problem %+v", apiErr)
+	}
+	if apiErr.Error() != "api error 429 too_many_requests: rate limit exceeded; limit: too large" {
+		t.Fatalf("unexpected message %q", apiErr.Error())
+	}
+
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/plain")
+	rec.WriteHeader(http.StatusBadGateway)
+	rec.Write([]byte("upstream is down\n"))
+
+	err = decodeError(rec.Result())
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || apiErr.Detail != "upstream is down" || apiErr.Title != "Bad Gateway" {
+		t.Fatalf("expected text response kept as detail, got %+v", apiErr)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Fatalf("expected undocumented status unwrapped to nothing, got %v", errors.Unwrap(err))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := New("http://localhost/api", Options{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		if got := c.backoff(attempt, ErrUnavailable); got != want {
+			t.Fatalf("backoff(%d): expected %s, got %s", attempt, want, got)
+		}
+	}
+	if got := c.backoff(0, &Error{Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}); got != 2*time.Second {
+		t.Fatalf("backoff: expected Retry-After of response, got %s", got)
+	}
+}
+
+func TestAll(t *testing.T) {
+	var pages []Page
+	items, err := All(context.Background(), func(ctx context.Context, page Page) ([]int64, error) {
+		pages = append(pages, page)
+		var result []int64
+		for i := page.Offset; i < min(page.Offset+page.Limit, 2*MaxLimit+1); i++ {
+			result = append(result, i)
+		}
+		return result, nil
+	})
+	if err != nil || len(items) != 2*MaxLimit+1 || len(pages) != 3 || pages[2].Offset != 2*MaxLimit {
+		t.Fatalf("All: expected %d items of 3 pages, got %d items of pages %v, %v", 2*MaxLimit+1, len(items), pages, err)
+	}
+
+	_, err = All(context.Background(), func(ctx context.Context, page Page) ([]int64, error) {
+		return nil, fmt.Errorf("list: %w", ErrForbidden)
+	})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("All: expected error of list, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors of response statuses, *Error unwraps to one of them so errors.Is tells classes
+// of errors apart and Error.Code tells exact ones
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrMethodNotAllowed   = errors.New("method not allowed")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrTooLarge           = errors.New("request entity too large")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrInternal           = errors.New("internal server error")
+	ErrUnavailable        = errors.New("service unavailable")
+	ErrTimeout            = errors.New("gateway timeout")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:            ErrBadRequest,
+	http.StatusUnauthorized:          ErrUnauthorized,
+	http.StatusForbidden:             ErrForbidden,
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusMethodNotAllowed:      ErrMethodNotAllowed,
+	http.StatusConflict:              ErrConflict,
+	http.StatusPreconditionFailed:    ErrPreconditionFailed,
+	http.StatusRequestEntityTooLarge: ErrTooLarge,
+	http.StatusTooManyRequests:       ErrTooManyRequests,
+	http.StatusInternalServerError:   ErrInternal,
+	http.StatusServiceUnavailable:    ErrUnavailable,
+	http.StatusGatewayTimeout:        ErrTimeout,
+}
+
+// Codes of problems returned by the api, see errorResponse in openapi.yml
+const (
+	CodeInvalidQueryParameters = "invalid_query_parameters"
+	CodeInvalidRequestBody     = "invalid_request_body"
+	CodeInvalidIfMatch         = "invalid_if_match"
+	CodeMissingFile            = "missing_file"
+	CodeMethodNotAllowed       = "method_not_allowed"
+	CodeTooManyRequests        = "too_many_requests"
+	CodeNotFound               = "not_found"
+	CodeInvalidCriteriaWeights = "invalid_criteria_weights"
+	CodeDuplicateCriteria      = "duplicate_criteria"
+	CodeMissingToken           = "missing_token"
+	CodeInvalidToken           = "invalid_token"
+	CodeTokenExpired           = "token_expired"
+	CodeInvalidCredentials     = "invalid_credentials"
+	CodeNotAuthenticated       = "not_authenticated"
+	CodeForbidden              = "forbidden"
+	CodeDeadlinePassed         = "deadline_passed"
+	CodeTenderNotFound         = "tender_not_found"
+	CodeBidNotFound            = "bid_not_found"
+	CodeAttachmentNotFound     = "attachment_not_found"
+	CodeOrganizationNotFound   = "organization_not_found"
+	CodeEmployeeNotFound       = "employee_not_found"
+	CodeResponsibleNotFound    = "responsible_not_found"
+	CodeReferenceNotFound      = "reference_not_found"
+	CodeDecisionNotAllowed     = "decision_not_allowed"
+	CodeEvaluationNotAllowed   = "evaluation_not_allowed"
+	CodeNoCriteria             = "no_criteria"
+	CodeUnknownCriterion       = "unknown_criterion"
+	CodeOrganizationRequired   = "organization_required"
+	CodeUnknownRole            = "unknown_role"
+	CodeAttachmentTooLarge     = "attachment_too_large"
+	CodeVersionMismatch        = "version_mismatch"
+	CodeVersionConflict        = "version_conflict"
+	CodeBidDecided             = "bid_decided"
+	CodeTenderClosed           = "tender_closed"
+	CodeEmployeeExists         = "employee_exists"
+	CodeResourceInUse          = "resource_in_use"
+	CodeConcurrentUpdate       = "concurrent_update"
+	CodeServiceUnavailable     = "service_unavailable"
+	CodeTimeout                = "timeout"
+	CodeRequestCanceled        = "request_canceled"
+	CodeInternalError          = "internal_error"
+	CodeUndocumentedRoute      = "undocumented_route"
+	CodeInvalidResponse        = "invalid_response"
+)
+
+// FieldError is invalid parameter or field of request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is RFC 7807 problem returned by the api
+type Error struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance"`
+	Code      string       `json:"code"`
+	RequestId string       `json:"requestId"`
+	Fields    []FieldError `json:"errors"`
+	// CurrentVersion is version of record on version mismatch and conflict
+	CurrentVersion *int64 `json:"currentVersion"`
+	// RetryAfter is wait asked by the api before repeating request
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("api error %d %s: %s", e.Status, e.Code, e.Detail)
+	for _, field := range e.Fields {
+		msg += fmt.Sprintf("; %s: %s", field.Field, field.Message)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return statusErrors[e.Status]
+}
+
+// decodeError reads problem from failed response and closes its body,
+// responses of other types keep their text as Detail
+func decodeError(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/problem+json" || json.Unmarshal(body, apiErr) != nil {
+		apiErr = &Error{
+			Title:  http.StatusText(resp.StatusCode),
+			Detail: strings.TrimSpace(string(body)),
+		}
+	}
+	apiErr.Status = resp.StatusCode
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
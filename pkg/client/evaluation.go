@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// NewCriterion is criterion of tender evaluation, weights of all criteria sum up to 100
+type NewCriterion struct {
+	Name   string `json:"name"`
+	Weight int64  `json:"weight"`
+}
+
+// NewScore is evaluation of bid by single criterion, from 0 to 100
+type NewScore struct {
+	CriterionId string `json:"criterionId"`
+	Value       int64  `json:"value"`
+}
+
+// SetTenderCriteria replaces criteria of tender
+func (c *Client) SetTenderCriteria(ctx context.Context, tenderId string, criteria []NewCriterion) ([]*Criterion, error) {
+	cl, err := jsonCall(http.MethodPut, "/tenders/"+escape(tenderId)+"/criteria", criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Criterion
+	err = c.do(ctx, cl, &result)
+	return result, err
+}
+
+func (c *Client) GetTenderCriteria(ctx context.Context, tenderId string) ([]*Criterion, error) {
+	var criteria []*Criterion
+	err := c.do(ctx, call{method: http.MethodGet, path: "/tenders/" + escape(tenderId) + "/criteria"}, &criteria)
+	return criteria, err
+}
+
+// ScoreBid evaluates bid by the employee, repeated evaluation replaces former one
+func (c *Client) ScoreBid(ctx context.Context, bidId string, scores []NewScore) (*BidScore, error) {
+	cl, err := jsonCall(http.MethodPut, "/bids/"+escape(bidId)+"/scores", scores)
+	if err != nil {
+		return nil, err
+	}
+	return c.bidScore(ctx, cl)
+}
+
+// GetBidScore returns weighted score of bid averaged over evaluators
+func (c *Client) GetBidScore(ctx context.Context, bidId string) (*BidScore, error) {
+	return c.bidScore(ctx, call{method: http.MethodGet, path: "/bids/" + escape(bidId) + "/score"})
+}
+
+func (c *Client) bidScore(ctx context.Context, cl call) (*BidScore, error) {
+	score := &BidScore{}
+	err := c.do(ctx, cl, score)
+	if err != nil {
+		return nil, err
+	}
+	return score, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import "github.com/avito-testirovanie-na-backend-1270/cnrprod1725729417-team-78417/zadanie-6105/internal/domain/models"
+
+// Responses are decoded into domain models, aliases let importers outside of the module name them
+type (
+	Tender         = models.Tender
+	Bid            = models.Bid
+	RankedBid      = models.RankedBid
+	Feedback       = models.Feedback
+	VersionsDiff   = models.VersionsDiff
+	FieldChange    = models.FieldChange
+	Criterion      = models.Criterion
+	BidScore       = models.BidScore
+	CriterionScore = models.CriterionScore
+	Attachment     = models.Attachment
+	AuditEntry     = models.AuditEntry
+	Organization   = models.Organization
+	Employee       = models.Employee
+	Member         = models.Member
+)
+
+// AnyVersion passed as expected version sends no If-Match header
+const AnyVersion int64 = 0
+
+const (
+	ServiceConstruction = "Construction"
+	ServiceDelivery     = "Delivery"
+	ServiceManufacture  = "Manufacture"
+)
+
+const (
+	TenderCreated   = "Created"
+	TenderPublished = "Published"
+	TenderClosed    = "Closed"
+)
+
+const (
+	BidCreated   = "Created"
+	BidPublished = "Published"
+	BidCanceled  = "Canceled"
+)
+
+const (
+	AuthorOrganization = "Organization"
+	AuthorUser         = "User"
+)
+
+const (
+	DecisionApproved = "Approved"
+	DecisionRejected = "Rejected"
+)
+
+// Orders of tender bids, empty one orders them by name
+const (
+	BidsByName     = ""
+	BidsByPrice    = "price"
+	BidsByDelivery = "delivery"
+)
+
+// Orders of tender bids ranking
+const (
+	RankByPrice = "price"
+	RankByScore = "score"
+)
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Organizations and employees are managed by admins only
+
+// OrganizationEdit holds fields of organization to create or change, empty ones are kept on change
+type OrganizationEdit struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// EmployeeEdit holds fields of employee to create or change, username can't be changed
+type EmployeeEdit struct {
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+}
+
+func (c *Client) CreateOrganization(ctx context.Context, org OrganizationEdit) (*Organization, error) {
+	cl, err := jsonCall(http.MethodPost, "/organizations", org)
+	if err != nil {
+		return nil, err
+	}
+	return c.organization(ctx, cl)
+}
+
+func (c *Client) ListOrganizations(ctx context.Context, page Page) ([]*Organization, error) {
+	var orgs []*Organization
+	err := c.do(ctx, call{method: http.MethodGet, path: "/organizations", query: page.query()}, &orgs)
+	return orgs, err
+}
+
+func (c *Client) GetOrganization(ctx context.Context, orgId string) (*Organization, error) {
+	return c.organization(ctx, call{method: http.MethodGet, path: "/organizations/" + escape(orgId)})
+}
+
+func (c *Client) EditOrganization(ctx context.Context, orgId string, edit OrganizationEdit) (*Organization, error) {
+	cl, err := jsonCall(http.MethodPatch, "/organizations/"+escape(orgId), edit)
+	if err != nil {
+		return nil, err
+	}
+	return c.organization(ctx, cl)
+}
+
+// DeleteOrganization deletes organization, one still in use fails with CodeResourceInUse
+func (c *Client) DeleteOrganization(ctx context.Context, orgId string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/organizations/" + escape(orgId)}, nil)
+}
+
+func (c *Client) ListResponsibles(ctx context.Context, orgId string) ([]*Member, error) {
+	var members []*Member
+	err := c.do(ctx, call{method: http.MethodGet, path: "/organizations/" + escape(orgId) + "/responsibles"}, &members)
+	return members, err
+}
+
+// GrantResponsible makes employee responsible for organization in role, empty role is default one
+func (c *Client) GrantResponsible(ctx context.Context, orgId, employeeId, role string) error {
+	var body interface{}
+	if role != "" {
+		body = map[string]string{"role": role}
+	}
+	cl, err := jsonCall(http.MethodPut, "/organizations/"+escape(orgId)+"/responsibles/"+escape(employeeId), body)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, cl, nil)
+}
+
+func (c *Client) RevokeResponsible(ctx context.Context, orgId, employeeId string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/organizations/" + escape(orgId) + "/responsibles/" + escape(employeeId)}, nil)
+}
+
+func (c *Client) CreateEmployee(ctx context.Context, emp EmployeeEdit) (*Employee, error) {
+	cl, err := jsonCall(http.MethodPost, "/employees", emp)
+	if err != nil {
+		return nil, err
+	}
+	return c.employee(ctx, cl)
+}
+
+func (c *Client) ListEmployees(ctx context.Context, page Page) ([]*Employee, error) {
+	var emps []*Employee
+	err := c.do(ctx, call{method: http.MethodGet, path: "/employees", query: page.query()}, &emps)
+	return emps, err
+}
+
+func (c *Client) GetEmployee(ctx context.Context, employeeId string) (*Employee, error) {
+	return c.employee(ctx, call{method: http.MethodGet, path: "/employees/" + escape(employeeId)})
+}
+
+func (c *Client) EditEmployee(ctx context.Context, employeeId string, edit EmployeeEdit) (*Employee, error) {
+	cl, err := jsonCall(http.MethodPatch, "/employees/"+escape(employeeId), edit)
+	if err != nil {
+		return nil, err
+	}
+	return c.employee(ctx, cl)
+}
+
+// DeleteEmployee deletes employee, one still in use fails with CodeResourceInUse
+func (c *Client) DeleteEmployee(ctx context.Context, employeeId string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/employees/" + escape(employeeId)}, nil)
+}
+
+// ListAudit lists changes made in organization, newest first
+func (c *Client) ListAudit(ctx context.Context, orgId string, page Page) ([]*AuditEntry, error) {
+	var entries []*AuditEntry
+	err := c.do(ctx, call{method: http.MethodGet, path: "/organizations/" + escape(orgId) + "/audit", query: page.query()}, &entries)
+	return entries, err
+}
+
+func (c *Client) organization(ctx context.Context, cl call) (*Organization, error) {
+	org := &Organization{}
+	err := c.do(ctx, cl, org)
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (c *Client) employee(ctx context.Context, cl call) (*Employee, error) {
+	emp := &Employee{}
+	err := c.do(ctx, cl, emp)
+	if err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
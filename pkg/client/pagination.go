@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// MaxLimit is the largest page the api returns
+const MaxLimit = 50
+
+// Page selects part of list, zero Limit means default page of the api, 5 items
+type Page struct {
+	Limit  int64
+	Offset int64
+}
+
+func (p Page) query() url.Values {
+	query := url.Values{}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Offset != 0 {
+		query.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+	return query
+}
+
+// All collects every item of list by requesting pages of MaxLimit until short one, e.g.
+//
+//	tenders, err := client.All(ctx, func(ctx context.Context, page client.Page) ([]*client.Tender, error) {
+//		return c.ListTenders(ctx, page, client.ServiceDelivery)
+//	})
+func All[T any](ctx context.Context, list func(ctx context.Context, page Page) ([]T, error)) ([]T, error) {
+	var result []T
+	page := Page{Limit: MaxLimit}
+	for {
+		items, err := list(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, items...)
+		if int64(len(items)) < page.Limit {
+			return result, nil
+		}
+		page.Offset += page.Limit
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// NewTender is tender to create on behalf of organization OrganizationId
+type NewTender struct {
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	ServiceType    string     `json:"serviceType"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	OrganizationId string     `json:"organizationId,omitempty"`
+}
+
+// TenderEdit holds changed fields of tender, empty ones are kept
+type TenderEdit struct {
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ServiceType string     `json:"serviceType,omitempty"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+}
+
+// ListTenders lists published tenders of serviceTypes, all types when none is given
+func (c *Client) ListTenders(ctx context.Context, page Page, serviceTypes ...string) ([]*Tender, error) {
+	cl := call{method: http.MethodGet, path: "/tenders", query: page.query()}
+	for _, serviceType := range serviceTypes {
+		cl.query.Add("service_type", serviceType)
+	}
+
+	var tenders []*Tender
+	err := c.do(ctx, cl, &tenders)
+	return tenders, err
+}
+
+func (c *Client) CreateTender(ctx context.Context, tender NewTender) (*Tender, error) {
+	cl, err := jsonCall(http.MethodPost, "/tenders/new", tender)
+	if err != nil {
+		return nil, err
+	}
+	return c.tender(ctx, cl)
+}
+
+// ListMyTenders lists tenders of organizations the employee is responsible for
+func (c *Client) ListMyTenders(ctx context.Context, page Page) ([]*Tender, error) {
+	var tenders []*Tender
+	err := c.do(ctx, call{method: http.MethodGet, path: "/tenders/my", query: page.query()}, &tenders)
+	return tenders, err
+}
+
+func (c *Client) GetTenderStatus(ctx context.Context, tenderId string) (string, error) {
+	var status string
+	err := c.do(ctx, call{method: http.MethodGet, path: "/tenders/" + escape(tenderId) + "/status"}, &status)
+	return status, err
+}
+
+// UpdateTenderStatus changes status of tender of expected version, see AnyVersion
+func (c *Client) UpdateTenderStatus(ctx context.Context, tenderId, status string, version int64) (*Tender, error) {
+	cl := call{
+		method: http.MethodPut,
+		path:   "/tenders/" + escape(tenderId) + "/status",
+		query:  url.Values{"status": {status}},
+	}
+	return c.tender(ctx, ifMatch(cl, version))
+}
+
+// EditTender changes tender of expected version, see AnyVersion
+func (c *Client) EditTender(ctx context.Context, tenderId string, edit TenderEdit, version int64) (*Tender, error) {
+	cl, err := jsonCall(http.MethodPatch, "/tenders/"+escape(tenderId)+"/edit", edit)
+	if err != nil {
+		return nil, err
+	}
+	return c.tender(ctx, ifMatch(cl, version))
+}
+
+// RollbackTender makes new version of tender with fields of version
+func (c *Client) RollbackTender(ctx context.Context, tenderId string, version int64) (*Tender, error) {
+	path := "/tenders/" + escape(tenderId) + "/rollback/" + strconv.FormatInt(version, 10)
+	return c.tender(ctx, call{method: http.MethodPut, path: path})
+}
+
+func (c *Client) ListTenderVersions(ctx context.Context, tenderId string, page Page) ([]*Tender, error) {
+	var tenders []*Tender
+	err := c.do(ctx, call{method: http.MethodGet, path: "/tenders/" + escape(tenderId) + "/versions", query: page.query()}, &tenders)
+	return tenders, err
+}
+
+func (c *Client) DiffTenderVersions(ctx context.Context, tenderId string, from, to int64) (*VersionsDiff, error) {
+	path := "/tenders/" + escape(tenderId) + "/versions/" + strconv.FormatInt(from, 10) + "/diff/" + strconv.FormatInt(to, 10)
+	diff := &VersionsDiff{}
+	err := c.do(ctx, call{method: http.MethodGet, path: path}, diff)
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func (c *Client) tender(ctx context.Context, cl call) (*Tender, error) {
+	tender := &Tender{}
+	err := c.do(ctx, cl, tender)
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}